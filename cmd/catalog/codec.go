@@ -0,0 +1,221 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/Aditya-Chowdhary/micro-movies/gen"
+	"github.com/Aditya-Chowdhary/micro-movies/metadata/pkg/model"
+)
+
+const (
+	formatJSONL = "jsonl"
+	formatCSV   = "csv"
+)
+
+// maxLineSize bounds the length of a JSON Lines row.
+const maxLineSize = 16 << 20
+
+// csvHeader lists the CSV columns. Translations are stored as a JSON object in a single column.
+var csvHeader = []string{"id", "title", "description", "director", "language", "translations"}
+
+type reader interface {
+	// Read returns the next row, or io.EOF once the input is exhausted.
+	// An *inputError means the input cannot be read any further. Any other
+	// error applies to the current row only and reading may continue.
+	Read() (*gen.Metadata, error)
+	// Line returns the line of the input where the row or error last
+	// returned by Read starts, counting from one.
+	Line() int
+}
+
+// inputError is an error reading the input itself rather than a single row.
+type inputError struct {
+	err error
+}
+
+func (e *inputError) Error() string { return e.err.Error() }
+
+func (e *inputError) Unwrap() error { return e.err }
+
+type writer interface {
+	Write(m *gen.Metadata) error
+	Flush() error
+}
+
+func newReader(format string, r io.Reader) reader {
+	if format == formatCSV {
+		cr := csv.NewReader(r)
+		cr.FieldsPerRecord = -1
+		return &csvReader{r: cr}
+	}
+	s := bufio.NewScanner(r)
+	s.Buffer(nil, maxLineSize)
+	return &jsonlReader{s: s}
+}
+
+func newWriter(format string, w io.Writer) writer {
+	if format == formatCSV {
+		return &csvWriter{w: csv.NewWriter(w)}
+	}
+	bw := bufio.NewWriter(w)
+	return &jsonlWriter{w: bw, enc: json.NewEncoder(bw)}
+}
+
+type jsonlReader struct {
+	s    *bufio.Scanner
+	line int
+}
+
+// Read returns the metadata on the next non-blank line.
+func (r *jsonlReader) Read() (*gen.Metadata, error) {
+	for r.s.Scan() {
+		r.line++
+		line := bytes.TrimSpace(r.s.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var m model.Metadata
+		if err := json.Unmarshal(line, &m); err != nil {
+			return nil, err
+		}
+		return model.MetadataToProto(&m), nil
+	}
+	if err := r.s.Err(); err != nil {
+		// The scanner stops on the line it failed to read.
+		r.line++
+		return nil, &inputError{err}
+	}
+	return nil, io.EOF
+}
+
+func (r *jsonlReader) Line() int {
+	return r.line
+}
+
+type jsonlWriter struct {
+	w   *bufio.Writer
+	enc *json.Encoder
+}
+
+func (w *jsonlWriter) Write(m *gen.Metadata) error {
+	return w.enc.Encode(model.MetadataFromProto(m))
+}
+
+func (w *jsonlWriter) Flush() error {
+	return w.w.Flush()
+}
+
+type csvReader struct {
+	r      *csv.Reader
+	header map[string]int
+	line   int
+}
+
+func (r *csvReader) Read() (*gen.Metadata, error) {
+	if r.header == nil {
+		record, err := r.read()
+		if err == io.EOF {
+			return nil, err
+		} else if err != nil {
+			// Rows cannot be read without the header.
+			return nil, &inputError{err}
+		}
+		r.header = map[string]int{}
+		for i, name := range record {
+			r.header[name] = i
+		}
+		if _, ok := r.header["id"]; !ok {
+			// Without ids no row can be imported.
+			return nil, &inputError{errors.New("csv header is missing the id column")}
+		}
+	}
+	record, err := r.read()
+	if err != nil {
+		return nil, csvError(err)
+	}
+	field := func(name string) string {
+		if i, ok := r.header[name]; ok && i < len(record) {
+			return record[i]
+		}
+		return ""
+	}
+	m := &model.Metadata{
+		ID:          field("id"),
+		Title:       field("title"),
+		Description: field("description"),
+		Director:    field("director"),
+		Language:    field("language"),
+	}
+	if t := field("translations"); t != "" {
+		if err := json.Unmarshal([]byte(t), &m.Translations); err != nil {
+			return nil, fmt.Errorf("translations: %w", err)
+		}
+	}
+	return model.MetadataToProto(m), nil
+}
+
+// read reads the next record and records the line it starts on. Records may
+// span several lines, and blank lines are skipped.
+func (r *csvReader) read() ([]string, error) {
+	record, err := r.r.Read()
+	var parseErr *csv.ParseError
+	if err == nil {
+		r.line, _ = r.r.FieldPos(0)
+	} else if errors.As(err, &parseErr) {
+		r.line = parseErr.StartLine
+	}
+	return record, err
+}
+
+func (r *csvReader) Line() int {
+	return r.line
+}
+
+// csvError classifies an error reading a CSV record: malformed records only
+// affect their row, other errors end the input.
+func csvError(err error) error {
+	var parseErr *csv.ParseError
+	if err == io.EOF || errors.As(err, &parseErr) {
+		return err
+	}
+	return &inputError{err}
+}
+
+type csvWriter struct {
+	w           *csv.Writer
+	wroteHeader bool
+}
+
+func (w *csvWriter) Write(m *gen.Metadata) error {
+	if !w.wroteHeader {
+		if err := w.w.Write(csvHeader); err != nil {
+			return err
+		}
+		w.wroteHeader = true
+	}
+	var translations string
+	if md := model.MetadataFromProto(m); len(md.Translations) > 0 {
+		b, err := json.Marshal(md.Translations)
+		if err != nil {
+			return err
+		}
+		translations = string(b)
+	}
+	return w.w.Write([]string{m.Id, m.Title, m.Description, m.Director, m.Language, translations})
+}
+
+func (w *csvWriter) Flush() error {
+	if !w.wroteHeader {
+		if err := w.w.Write(csvHeader); err != nil {
+			return err
+		}
+	}
+	w.w.Flush()
+	return w.w.Error()
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// readAll reads r until io.EOF or an *inputError, returning the ids of the
+// rows read, the row errors and the line of every row and error.
+func readAll(t *testing.T, r reader) (ids []string, rowErrs []error, lines []int, inputErr error) {
+	t.Helper()
+	for i := 0; i < 100; i++ {
+		m, err := r.Read()
+		if err == io.EOF {
+			return ids, rowErrs, lines, nil
+		}
+		lines = append(lines, r.Line())
+		var ie *inputError
+		switch {
+		case errors.As(err, &ie):
+			return ids, rowErrs, lines, err
+		case err != nil:
+			rowErrs = append(rowErrs, err)
+		default:
+			ids = append(ids, m.Id)
+		}
+	}
+	t.Fatal("reader did not stop")
+	return nil, nil, nil, nil
+}
+
+func TestJSONLReader(t *testing.T) {
+	long := strings.Repeat("x", 2*bufio.MaxScanTokenSize)
+	tests := []struct {
+		desc         string
+		input        string
+		wantIDs      []string
+		wantRowErrs  int
+		wantLines    []int
+		wantInputErr error
+	}{
+		{
+			desc:      "rows",
+			input:     `{"id":"1"}` + "\n" + `{"id":"2"}`,
+			wantIDs:   []string{"1", "2"},
+			wantLines: []int{1, 2},
+		},
+		{
+			desc:      "blank lines are skipped",
+			input:     "\n" + `{"id":"1"}` + "\n   \n\n" + `{"id":"2"}` + "\n\n",
+			wantIDs:   []string{"1", "2"},
+			wantLines: []int{2, 5},
+		},
+		{
+			desc:        "malformed line",
+			input:       `{"id":"1"}` + "\n{not json\n" + `{"id":"2"}`,
+			wantIDs:     []string{"1", "2"},
+			wantRowErrs: 1,
+			wantLines:   []int{1, 2, 3},
+		},
+		{
+			desc:      "line longer than the default scanner limit",
+			input:     `{"id":"1","description":"` + long + `"}` + "\n" + `{"id":"2"}`,
+			wantIDs:   []string{"1", "2"},
+			wantLines: []int{1, 2},
+		},
+		{
+			desc:         "line longer than maxLineSize",
+			input:        `{"id":"1"}` + "\n" + `{"id":"2","description":"` + strings.Repeat("x", maxLineSize) + `"}` + "\n" + `{"id":"3"}`,
+			wantIDs:      []string{"1"},
+			wantLines:    []int{1, 2},
+			wantInputErr: bufio.ErrTooLong,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			ids, rowErrs, lines, inputErr := readAll(t, newReader(formatJSONL, strings.NewReader(tt.input)))
+			assert.Equal(t, tt.wantIDs, ids)
+			assert.Len(t, rowErrs, tt.wantRowErrs)
+			assert.Equal(t, tt.wantLines, lines)
+			if tt.wantInputErr != nil {
+				require.Error(t, inputErr)
+				assert.ErrorIs(t, inputErr, tt.wantInputErr)
+			} else {
+				assert.NoError(t, inputErr)
+			}
+		})
+	}
+}
+
+func TestCSVReader(t *testing.T) {
+	tests := []struct {
+		desc         string
+		input        string
+		wantIDs      []string
+		wantRowErrs  int
+		wantLines    []int
+		wantInputErr string
+	}{
+		{
+			desc:      "rows",
+			input:     "title,id\nOne,1\nTwo,2\n",
+			wantIDs:   []string{"1", "2"},
+			wantLines: []int{2, 3},
+		},
+		{
+			desc:      "multi-line fields and blank lines",
+			input:     "id,description\n1,\"first\nsecond\"\n\n2,Two\n",
+			wantIDs:   []string{"1", "2"},
+			wantLines: []int{2, 5},
+		},
+		{
+			desc:        "unterminated quote",
+			input:       "id,title\n1,One\n2,\"Two\n3,Three\n",
+			wantIDs:     []string{"1"},
+			wantRowErrs: 1,
+			wantLines:   []int{2, 3},
+		},
+		{
+			desc:         "header without id column",
+			input:        "title\nOne\nTwo\n",
+			wantLines:    []int{1},
+			wantInputErr: "csv header is missing the id column",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			ids, rowErrs, lines, inputErr := readAll(t, newReader(formatCSV, strings.NewReader(tt.input)))
+			assert.Equal(t, tt.wantIDs, ids)
+			assert.Len(t, rowErrs, tt.wantRowErrs)
+			assert.Equal(t, tt.wantLines, lines)
+			if tt.wantInputErr != "" {
+				assert.EqualError(t, inputErr, tt.wantInputErr)
+			} else {
+				assert.NoError(t, inputErr)
+			}
+		})
+	}
+}
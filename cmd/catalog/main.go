@@ -0,0 +1,167 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Aditya-Chowdhary/micro-movies/gen"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+const usage = `Usage: catalog <import|export> [flags]
+
+Imports or exports movie metadata through the metadata service.
+
+  catalog import -file movies.jsonl
+  catalog export -format csv -file movies.csv
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	fs := flag.NewFlagSet(os.Args[1], flag.ExitOnError)
+	addr := fs.String("addr", "localhost:8081", "metadata service address")
+	file := fs.String("file", "", "file to read from or write to, stdin/stdout if empty")
+	format := fs.String("format", "", "jsonl or csv, inferred from the file extension if empty")
+	author := fs.String("author", "catalog", "author recorded on imported revisions")
+	fs.Parse(os.Args[2:])
+
+	f, err := resolveFormat(*format, *file)
+	if err != nil {
+		fail(err)
+	}
+
+	conn, err := grpc.Dial(*addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		fail(err)
+	}
+	defer conn.Close()
+	client := gen.NewMetadataServiceClient(conn)
+	ctx := context.Background()
+
+	switch os.Args[1] {
+	case "import":
+		in := os.Stdin
+		if *file != "" {
+			if in, err = os.Open(*file); err != nil {
+				fail(err)
+			}
+			defer in.Close()
+		}
+		if err := runImport(ctx, client, newReader(f, in), *author); err != nil {
+			fail(err)
+		}
+	case "export":
+		out := os.Stdout
+		if *file != "" {
+			if out, err = os.Create(*file); err != nil {
+				fail(err)
+			}
+			defer out.Close()
+		}
+		if err := runExport(ctx, client, newWriter(f, out)); err != nil {
+			fail(err)
+		}
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+}
+
+// runImport streams every readable row to the metadata service. Rows that fail to
+// parse locally or to be written remotely are reported without stopping the import.
+// An unreadable input stops the import after the rows read so far.
+// Failures are reported with the line of the input the row starts on.
+func runImport(ctx context.Context, client gen.MetadataServiceClient, r reader, author string) error {
+	stream, err := client.ImportMetadata(ctx)
+	if err != nil {
+		return err
+	}
+
+	// sent maps the position of a message in the stream to its line in the input.
+	var sent []int
+	failed := 0
+	var readErr error
+	for {
+		m, err := r.Read()
+		var inputErr *inputError
+		if err == io.EOF {
+			break
+		} else if errors.As(err, &inputErr) {
+			// Finish the stream so that the rows already sent are reported.
+			readErr = fmt.Errorf("line %d: %w", r.Line(), err)
+			break
+		} else if err != nil {
+			fmt.Fprintf(os.Stderr, "line %d: %v\n", r.Line(), err)
+			failed++
+			continue
+		}
+		if err := stream.Send(&gen.ImportMetadataRequest{Metadata: m, Author: author}); err != nil {
+			return err
+		}
+		sent = append(sent, r.Line())
+	}
+
+	res, err := stream.CloseAndRecv()
+	if err != nil {
+		return err
+	}
+	for _, e := range res.Errors {
+		line := e.Row
+		if e.Row > 0 && int(e.Row) <= len(sent) {
+			line = int64(sent[e.Row-1])
+		}
+		fmt.Fprintf(os.Stderr, "line %d (%s): %s\n", line, e.MovieId, e.Message)
+	}
+	failed += len(res.Errors)
+	fmt.Fprintf(os.Stderr, "Imported %d movies, %d failed\n", res.Imported, failed)
+	return readErr
+}
+
+func runExport(ctx context.Context, client gen.MetadataServiceClient, w writer) error {
+	stream, err := client.ExportMetadata(ctx, &gen.ExportMetadataRequest{})
+	if err != nil {
+		return err
+	}
+	for {
+		res, err := stream.Recv()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+		if err := w.Write(res.Metadata); err != nil {
+			return err
+		}
+	}
+	return w.Flush()
+}
+
+func resolveFormat(format, file string) (string, error) {
+	if format == "" {
+		format = strings.TrimPrefix(filepath.Ext(file), ".")
+	}
+	switch format {
+	case formatJSONL, formatCSV:
+		return format, nil
+	case "":
+		return formatJSONL, nil
+	}
+	return "", fmt.Errorf("unsupported format %q, expected jsonl or csv", format)
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, err)
+	os.Exit(1)
+}
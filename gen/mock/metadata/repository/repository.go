@@ -11,9 +11,10 @@ package repository
 
 import (
 	context "context"
-	model "github.com/Aditya-Chowdhary/micro-movies/metadata/pkg/model"
 	reflect "reflect"
+	time "time"
 
+	model "github.com/Aditya-Chowdhary/micro-movies/metadata/pkg/model"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -41,30 +42,90 @@ func (m *MockmetadataRepository) EXPECT() *MockmetadataRepositoryMockRecorder {
 }
 
 // Get mocks base method.
-func (m *MockmetadataRepository) Get(arg0 context.Context, id string) (*model.Metadata, error) {
+func (m *MockmetadataRepository) Get(ctx context.Context, id string) (*model.Metadata, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Get", arg0, id)
+	ret := m.ctrl.Call(m, "Get", ctx, id)
 	ret0, _ := ret[0].(*model.Metadata)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Get indicates an expected call of Get.
-func (mr *MockmetadataRepositoryMockRecorder) Get(arg0, id any) *gomock.Call {
+func (mr *MockmetadataRepositoryMockRecorder) Get(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockmetadataRepository)(nil).Get), ctx, id)
+}
+
+// GetRevision mocks base method.
+func (m *MockmetadataRepository) GetRevision(ctx context.Context, id string, version int64) (*model.Revision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRevision", ctx, id, version)
+	ret0, _ := ret[0].(*model.Revision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRevision indicates an expected call of GetRevision.
+func (mr *MockmetadataRepositoryMockRecorder) GetRevision(ctx, id, version any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRevision", reflect.TypeOf((*MockmetadataRepository)(nil).GetRevision), ctx, id, version)
+}
+
+// List mocks base method.
+func (m *MockmetadataRepository) List(ctx context.Context) ([]*model.Metadata, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx)
+	ret0, _ := ret[0].([]*model.Metadata)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockmetadataRepositoryMockRecorder) List(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockmetadataRepository)(nil).List), ctx)
+}
+
+// ListRevisions mocks base method.
+func (m *MockmetadataRepository) ListRevisions(ctx context.Context, id string) ([]*model.Revision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRevisions", ctx, id)
+	ret0, _ := ret[0].([]*model.Revision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRevisions indicates an expected call of ListRevisions.
+func (mr *MockmetadataRepositoryMockRecorder) ListRevisions(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRevisions", reflect.TypeOf((*MockmetadataRepository)(nil).ListRevisions), ctx, id)
+}
+
+// Purge mocks base method.
+func (m *MockmetadataRepository) Purge(ctx context.Context, deletedBefore time.Time) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Purge", ctx, deletedBefore)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Purge indicates an expected call of Purge.
+func (mr *MockmetadataRepositoryMockRecorder) Purge(ctx, deletedBefore any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockmetadataRepository)(nil).Get), arg0, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Purge", reflect.TypeOf((*MockmetadataRepository)(nil).Purge), ctx, deletedBefore)
 }
 
 // Put mocks base method.
-func (m_2 *MockmetadataRepository) Put(ctx context.Context, id string, m *model.Metadata) error {
+func (m_2 *MockmetadataRepository) Put(ctx context.Context, id string, m *model.Metadata, rev *model.Revision) error {
 	m_2.ctrl.T.Helper()
-	ret := m_2.ctrl.Call(m_2, "Put", ctx, id, m)
+	ret := m_2.ctrl.Call(m_2, "Put", ctx, id, m, rev)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Put indicates an expected call of Put.
-func (mr *MockmetadataRepositoryMockRecorder) Put(ctx, id, m any) *gomock.Call {
+func (mr *MockmetadataRepositoryMockRecorder) Put(ctx, id, m, rev any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Put", reflect.TypeOf((*MockmetadataRepository)(nil).Put), ctx, id, m)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Put", reflect.TypeOf((*MockmetadataRepository)(nil).Put), ctx, id, m, rev)
 }
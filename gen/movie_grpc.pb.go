@@ -19,8 +19,14 @@ import (
 const _ = grpc.SupportPackageIsVersion7
 
 const (
-	MetadataService_GetMetadata_FullMethodName = "/MetadataService/GetMetadata"
-	MetadataService_PutMetadata_FullMethodName = "/MetadataService/PutMetadata"
+	MetadataService_GetMetadata_FullMethodName           = "/MetadataService/GetMetadata"
+	MetadataService_PutMetadata_FullMethodName           = "/MetadataService/PutMetadata"
+	MetadataService_ListMetadataRevisions_FullMethodName = "/MetadataService/ListMetadataRevisions"
+	MetadataService_RevertMetadata_FullMethodName        = "/MetadataService/RevertMetadata"
+	MetadataService_ImportMetadata_FullMethodName        = "/MetadataService/ImportMetadata"
+	MetadataService_ExportMetadata_FullMethodName        = "/MetadataService/ExportMetadata"
+	MetadataService_DeleteMetadata_FullMethodName        = "/MetadataService/DeleteMetadata"
+	MetadataService_RestoreMetadata_FullMethodName       = "/MetadataService/RestoreMetadata"
 )
 
 // MetadataServiceClient is the client API for MetadataService service.
@@ -29,6 +35,12 @@ const (
 type MetadataServiceClient interface {
 	GetMetadata(ctx context.Context, in *GetMetadataRequest, opts ...grpc.CallOption) (*GetMetadataResponse, error)
 	PutMetadata(ctx context.Context, in *PutMetadataRequest, opts ...grpc.CallOption) (*PutMetadataResponse, error)
+	ListMetadataRevisions(ctx context.Context, in *ListMetadataRevisionsRequest, opts ...grpc.CallOption) (*ListMetadataRevisionsResponse, error)
+	RevertMetadata(ctx context.Context, in *RevertMetadataRequest, opts ...grpc.CallOption) (*RevertMetadataResponse, error)
+	ImportMetadata(ctx context.Context, opts ...grpc.CallOption) (MetadataService_ImportMetadataClient, error)
+	ExportMetadata(ctx context.Context, in *ExportMetadataRequest, opts ...grpc.CallOption) (MetadataService_ExportMetadataClient, error)
+	DeleteMetadata(ctx context.Context, in *DeleteMetadataRequest, opts ...grpc.CallOption) (*DeleteMetadataResponse, error)
+	RestoreMetadata(ctx context.Context, in *RestoreMetadataRequest, opts ...grpc.CallOption) (*RestoreMetadataResponse, error)
 }
 
 type metadataServiceClient struct {
@@ -57,12 +69,120 @@ func (c *metadataServiceClient) PutMetadata(ctx context.Context, in *PutMetadata
 	return out, nil
 }
 
+func (c *metadataServiceClient) ListMetadataRevisions(ctx context.Context, in *ListMetadataRevisionsRequest, opts ...grpc.CallOption) (*ListMetadataRevisionsResponse, error) {
+	out := new(ListMetadataRevisionsResponse)
+	err := c.cc.Invoke(ctx, MetadataService_ListMetadataRevisions_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *metadataServiceClient) RevertMetadata(ctx context.Context, in *RevertMetadataRequest, opts ...grpc.CallOption) (*RevertMetadataResponse, error) {
+	out := new(RevertMetadataResponse)
+	err := c.cc.Invoke(ctx, MetadataService_RevertMetadata_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *metadataServiceClient) ImportMetadata(ctx context.Context, opts ...grpc.CallOption) (MetadataService_ImportMetadataClient, error) {
+	stream, err := c.cc.NewStream(ctx, &MetadataService_ServiceDesc.Streams[0], MetadataService_ImportMetadata_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &metadataServiceImportMetadataClient{stream}
+	return x, nil
+}
+
+type MetadataService_ImportMetadataClient interface {
+	Send(*ImportMetadataRequest) error
+	CloseAndRecv() (*ImportMetadataResponse, error)
+	grpc.ClientStream
+}
+
+type metadataServiceImportMetadataClient struct {
+	grpc.ClientStream
+}
+
+func (x *metadataServiceImportMetadataClient) Send(m *ImportMetadataRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *metadataServiceImportMetadataClient) CloseAndRecv() (*ImportMetadataResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(ImportMetadataResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *metadataServiceClient) ExportMetadata(ctx context.Context, in *ExportMetadataRequest, opts ...grpc.CallOption) (MetadataService_ExportMetadataClient, error) {
+	stream, err := c.cc.NewStream(ctx, &MetadataService_ServiceDesc.Streams[1], MetadataService_ExportMetadata_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &metadataServiceExportMetadataClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type MetadataService_ExportMetadataClient interface {
+	Recv() (*ExportMetadataResponse, error)
+	grpc.ClientStream
+}
+
+type metadataServiceExportMetadataClient struct {
+	grpc.ClientStream
+}
+
+func (x *metadataServiceExportMetadataClient) Recv() (*ExportMetadataResponse, error) {
+	m := new(ExportMetadataResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *metadataServiceClient) DeleteMetadata(ctx context.Context, in *DeleteMetadataRequest, opts ...grpc.CallOption) (*DeleteMetadataResponse, error) {
+	out := new(DeleteMetadataResponse)
+	err := c.cc.Invoke(ctx, MetadataService_DeleteMetadata_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *metadataServiceClient) RestoreMetadata(ctx context.Context, in *RestoreMetadataRequest, opts ...grpc.CallOption) (*RestoreMetadataResponse, error) {
+	out := new(RestoreMetadataResponse)
+	err := c.cc.Invoke(ctx, MetadataService_RestoreMetadata_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // MetadataServiceServer is the server API for MetadataService service.
 // All implementations must embed UnimplementedMetadataServiceServer
 // for forward compatibility
 type MetadataServiceServer interface {
 	GetMetadata(context.Context, *GetMetadataRequest) (*GetMetadataResponse, error)
 	PutMetadata(context.Context, *PutMetadataRequest) (*PutMetadataResponse, error)
+	ListMetadataRevisions(context.Context, *ListMetadataRevisionsRequest) (*ListMetadataRevisionsResponse, error)
+	RevertMetadata(context.Context, *RevertMetadataRequest) (*RevertMetadataResponse, error)
+	ImportMetadata(MetadataService_ImportMetadataServer) error
+	ExportMetadata(*ExportMetadataRequest, MetadataService_ExportMetadataServer) error
+	DeleteMetadata(context.Context, *DeleteMetadataRequest) (*DeleteMetadataResponse, error)
+	RestoreMetadata(context.Context, *RestoreMetadataRequest) (*RestoreMetadataResponse, error)
 	mustEmbedUnimplementedMetadataServiceServer()
 }
 
@@ -76,6 +196,24 @@ func (UnimplementedMetadataServiceServer) GetMetadata(context.Context, *GetMetad
 func (UnimplementedMetadataServiceServer) PutMetadata(context.Context, *PutMetadataRequest) (*PutMetadataResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method PutMetadata not implemented")
 }
+func (UnimplementedMetadataServiceServer) ListMetadataRevisions(context.Context, *ListMetadataRevisionsRequest) (*ListMetadataRevisionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListMetadataRevisions not implemented")
+}
+func (UnimplementedMetadataServiceServer) RevertMetadata(context.Context, *RevertMetadataRequest) (*RevertMetadataResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevertMetadata not implemented")
+}
+func (UnimplementedMetadataServiceServer) ImportMetadata(MetadataService_ImportMetadataServer) error {
+	return status.Errorf(codes.Unimplemented, "method ImportMetadata not implemented")
+}
+func (UnimplementedMetadataServiceServer) ExportMetadata(*ExportMetadataRequest, MetadataService_ExportMetadataServer) error {
+	return status.Errorf(codes.Unimplemented, "method ExportMetadata not implemented")
+}
+func (UnimplementedMetadataServiceServer) DeleteMetadata(context.Context, *DeleteMetadataRequest) (*DeleteMetadataResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteMetadata not implemented")
+}
+func (UnimplementedMetadataServiceServer) RestoreMetadata(context.Context, *RestoreMetadataRequest) (*RestoreMetadataResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreMetadata not implemented")
+}
 func (UnimplementedMetadataServiceServer) mustEmbedUnimplementedMetadataServiceServer() {}
 
 // UnsafeMetadataServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -125,6 +263,125 @@ func _MetadataService_PutMetadata_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _MetadataService_ListMetadataRevisions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListMetadataRevisionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MetadataServiceServer).ListMetadataRevisions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MetadataService_ListMetadataRevisions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MetadataServiceServer).ListMetadataRevisions(ctx, req.(*ListMetadataRevisionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MetadataService_RevertMetadata_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevertMetadataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MetadataServiceServer).RevertMetadata(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MetadataService_RevertMetadata_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MetadataServiceServer).RevertMetadata(ctx, req.(*RevertMetadataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MetadataService_ImportMetadata_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(MetadataServiceServer).ImportMetadata(&metadataServiceImportMetadataServer{stream})
+}
+
+type MetadataService_ImportMetadataServer interface {
+	SendAndClose(*ImportMetadataResponse) error
+	Recv() (*ImportMetadataRequest, error)
+	grpc.ServerStream
+}
+
+type metadataServiceImportMetadataServer struct {
+	grpc.ServerStream
+}
+
+func (x *metadataServiceImportMetadataServer) SendAndClose(m *ImportMetadataResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *metadataServiceImportMetadataServer) Recv() (*ImportMetadataRequest, error) {
+	m := new(ImportMetadataRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _MetadataService_ExportMetadata_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExportMetadataRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(MetadataServiceServer).ExportMetadata(m, &metadataServiceExportMetadataServer{stream})
+}
+
+type MetadataService_ExportMetadataServer interface {
+	Send(*ExportMetadataResponse) error
+	grpc.ServerStream
+}
+
+type metadataServiceExportMetadataServer struct {
+	grpc.ServerStream
+}
+
+func (x *metadataServiceExportMetadataServer) Send(m *ExportMetadataResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _MetadataService_DeleteMetadata_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteMetadataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MetadataServiceServer).DeleteMetadata(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MetadataService_DeleteMetadata_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MetadataServiceServer).DeleteMetadata(ctx, req.(*DeleteMetadataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MetadataService_RestoreMetadata_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreMetadataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MetadataServiceServer).RestoreMetadata(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MetadataService_RestoreMetadata_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MetadataServiceServer).RestoreMetadata(ctx, req.(*RestoreMetadataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // MetadataService_ServiceDesc is the grpc.ServiceDesc for MetadataService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -140,8 +397,35 @@ var MetadataService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "PutMetadata",
 			Handler:    _MetadataService_PutMetadata_Handler,
 		},
+		{
+			MethodName: "ListMetadataRevisions",
+			Handler:    _MetadataService_ListMetadataRevisions_Handler,
+		},
+		{
+			MethodName: "RevertMetadata",
+			Handler:    _MetadataService_RevertMetadata_Handler,
+		},
+		{
+			MethodName: "DeleteMetadata",
+			Handler:    _MetadataService_DeleteMetadata_Handler,
+		},
+		{
+			MethodName: "RestoreMetadata",
+			Handler:    _MetadataService_RestoreMetadata_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ImportMetadata",
+			Handler:       _MetadataService_ImportMetadata_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "ExportMetadata",
+			Handler:       _MetadataService_ExportMetadata_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "movie.proto",
 }
 
@@ -0,0 +1,133 @@
+package grpcutil
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"net/url"
+	"slices"
+	"sync"
+
+	"github.com/Aditya-Chowdhary/micro-movies/pkg/discovery"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// roundRobinConfig is the default service config balancing calls across all
+// resolved instances.
+const roundRobinConfig = `{"loadBalancingConfig": [{"round_robin": {}}]}`
+
+// ConnManager maintains long-lived gRPC connections to services discovered
+// through a registry, so gateways do not dial on every call.
+//
+// Each service gets a single client connection resolved through a
+// registry:///<service> target. Instances are picked with gRPC's round_robin
+// balancer, and connections to instances are opened and closed as they come
+// and go from the registry.
+type ConnManager struct {
+	registry discovery.Registry
+	opts     []grpc.DialOption
+
+	mu      sync.Mutex
+	zone    ZonePreference
+	filters map[string]map[string]string
+	conns   map[string]*grpc.ClientConn
+	closed  bool
+}
+
+// NewConnManager creates a new connection manager for services in registry.
+// Connections are dialled with opts in addition to insecure transport
+// credentials, tracing and round robin balancing. A default service config
+// in opts replaces round robin with another balancer.
+func NewConnManager(registry discovery.Registry, opts ...grpc.DialOption) *ConnManager {
+	return &ConnManager{
+		registry: registry,
+		opts: append([]grpc.DialOption{
+			grpc.WithTransportCredentials(insecure.NewCredentials()),
+			grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+			grpc.WithDefaultServiceConfig(roundRobinConfig),
+		}, opts...),
+		filters: map[string]map[string]string{},
+		conns:   map[string]*grpc.ClientConn{},
+	}
+}
+
+// PreferZone keeps calls within a zone while it has enough healthy instances
+// of the called service. It must be called before the first connection.
+func (m *ConnManager) PreferZone(zone ZonePreference) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.zone = zone
+}
+
+// SetFilter restricts connections to instances of serviceName whose metadata
+// matches filter, e.g. only version=v2 instances. It must be called before
+// the first connection to serviceName.
+func (m *ConnManager) SetFilter(serviceName string, filter map[string]string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.filters[serviceName] = filter
+}
+
+// Conn returns the connection to serviceName, dialling it on first use.
+// Callers must not close the returned connection.
+func (m *ConnManager) Conn(ctx context.Context, serviceName string) (*grpc.ClientConn, error) {
+	return m.ConnMatching(ctx, serviceName, nil)
+}
+
+// ConnMatching returns the connection to instances of serviceName whose
+// metadata matches both the filter set for serviceName and match, dialling it
+// on first use. Where both set a key, match takes precedence. Callers must not
+// close the returned connection.
+func (m *ConnManager) ConnMatching(_ context.Context, serviceName string, match map[string]string) (*grpc.ClientConn, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	filter := maps.Clone(m.filters[serviceName])
+	if filter == nil {
+		filter = map[string]string{}
+	}
+	maps.Copy(filter, match)
+	target := Target(serviceName, filter)
+	if m.closed {
+		return nil, errors.New("connection manager is closed")
+	}
+	if conn, ok := m.conns[target]; ok {
+		return conn, nil
+	}
+	resolvers := grpc.WithResolvers(NewResolverBuilder(m.registry, m.zone))
+	conn, err := grpc.Dial(target, append(slices.Clip(m.opts), resolvers)...)
+	if err != nil {
+		return nil, err
+	}
+	m.conns[target] = conn
+	return conn, nil
+}
+
+// Close closes all managed connections.
+func (m *ConnManager) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.closed = true
+	var errs []error
+	for _, conn := range m.conns {
+		errs = append(errs, conn.Close())
+	}
+	m.conns = map[string]*grpc.ClientConn{}
+	return errors.Join(errs...)
+}
+
+// Target returns the registry target resolving instances of serviceName
+// matching filter.
+func Target(serviceName string, filter map[string]string) string {
+	u := url.URL{Scheme: Scheme, Path: "/" + serviceName}
+	if len(filter) > 0 {
+		q := url.Values{}
+		for k, v := range filter {
+			q.Set(k, v)
+		}
+		u.RawQuery = q.Encode()
+	}
+	return u.String()
+}
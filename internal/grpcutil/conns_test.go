@@ -0,0 +1,68 @@
+package grpcutil
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Aditya-Chowdhary/micro-movies/internal/grpcutil/grpctest"
+	"github.com/Aditya-Chowdhary/micro-movies/pkg/discovery/memory"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestConnManager(t *testing.T) {
+	ctx := context.Background()
+	a, b := grpctest.StartCountingServer(t), grpctest.StartCountingServer(t)
+	registry := memory.NewRegistry()
+	defer registry.Close()
+	require.NoError(t, registry.Register(ctx, "a", "svc", a.Addr, nil))
+	require.NoError(t, registry.Register(ctx, "b", "svc", b.Addr, nil))
+
+	m := NewConnManager(registry)
+	defer m.Close()
+
+	conn, err := m.Conn(ctx, "svc")
+	require.NoError(t, err)
+	again, err := m.Conn(ctx, "svc")
+	require.NoError(t, err)
+	assert.Same(t, conn, again, "connections are reused")
+
+	client := healthpb.NewHealthClient(conn)
+	for i := 0; i < 10; i++ {
+		_, err := client.Check(ctx, &healthpb.HealthCheckRequest{}, grpc.WaitForReady(true))
+		require.NoError(t, err)
+	}
+	assert.Positive(t, a.Count(), "calls are balanced across instances")
+	assert.Positive(t, b.Count(), "calls are balanced across instances")
+
+	require.NoError(t, m.Close())
+	assert.Equal(t, connectivity.Shutdown, conn.GetState())
+	_, err = m.Conn(ctx, "svc")
+	assert.Error(t, err)
+}
+
+func TestConnManagerFilters(t *testing.T) {
+	ctx := context.Background()
+	registry := memory.NewRegistry()
+	defer registry.Close()
+	m := NewConnManager(registry)
+	defer m.Close()
+	m.SetFilter("svc", map[string]string{"version": "v2", "track": "stable"})
+
+	conn, err := m.Conn(ctx, "svc")
+	require.NoError(t, err)
+	assert.Equal(t, Target("svc", map[string]string{"version": "v2", "track": "stable"}), conn.Target())
+
+	conn, err = m.ConnMatching(ctx, "svc", map[string]string{"track": "canary", "zone": "a"})
+	require.NoError(t, err)
+	assert.Equal(t, Target("svc", map[string]string{"version": "v2", "track": "canary", "zone": "a"}), conn.Target(),
+		"matches are combined with the service filter")
+
+	conn, err = m.ConnMatching(ctx, "other", nil)
+	require.NoError(t, err)
+	assert.Equal(t, Target("other", nil), conn.Target())
+}
@@ -0,0 +1,48 @@
+// Package grpctest provides gRPC servers for tests.
+package grpctest
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// CountingServer is a gRPC health server counting the calls it receives.
+type CountingServer struct {
+	Addr string
+
+	mu    sync.Mutex
+	calls int
+}
+
+// StartCountingServer starts a CountingServer on a local port. It is stopped
+// when the test ends.
+func StartCountingServer(t *testing.T) *CountingServer {
+	t.Helper()
+	lis, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	s := &CountingServer{Addr: lis.Addr().String()}
+	srv := grpc.NewServer(grpc.UnaryInterceptor(func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		s.mu.Lock()
+		s.calls++
+		s.mu.Unlock()
+		return handler(ctx, req)
+	}))
+	healthpb.RegisterHealthServer(srv, health.NewServer())
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+	return s
+}
+
+// Count returns the number of calls received so far.
+func (s *CountingServer) Count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls
+}
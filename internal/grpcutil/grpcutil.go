@@ -1,24 +0,0 @@
-package grpcutil
-
-import (
-	"context"
-	"math/rand"
-
-	"github.com/Aditya-Chowdhary/micro-movies/pkg/discovery"
-
-	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
-)
-
-// ServiceConnection attempts to select a random service instance and returns a gRPC connection to it.
-func ServiceConnection(ctx context.Context, serviceName string, registry discovery.Registry) (*grpc.ClientConn, error) {
-	addrs, err := registry.ServiceAddresses(ctx, serviceName)
-	if err != nil {
-		return nil, err
-	}
-	return grpc.Dial(
-		addrs[rand.Intn(len(addrs))],
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
-		grpc.WithStatsHandler(otelgrpc.NewClientHandler()))
-}
@@ -0,0 +1,98 @@
+package grpcutil
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Aditya-Chowdhary/micro-movies/pkg/discovery"
+
+	"google.golang.org/grpc/resolver"
+)
+
+// Scheme is the target scheme resolved through a discovery registry,
+// e.g. registry:///rating.
+const Scheme = "registry"
+
+// ZonePreference keeps calls within a zone while it has enough healthy instances.
+type ZonePreference struct {
+	// Zone is the zone of the caller. Empty disables the preference.
+	Zone string
+	// MinInstances is the number of healthy instances in Zone below which
+	// calls spill over to other zones. Zero means one.
+	MinInstances int
+}
+
+// NewResolverBuilder creates a gRPC resolver builder for registry:///<service>
+// targets. Addresses of the service are watched in registry and pushed to the
+// client connection whenever they change. Query parameters of the target
+// restrict instances to those with matching metadata, e.g.
+// registry:///rating?version=v2, and instances in the preferred zone are
+// pushed alone while there are enough of them.
+func NewResolverBuilder(registry discovery.Registry, zone ZonePreference) resolver.Builder {
+	return &resolverBuilder{registry, zone}
+}
+
+type resolverBuilder struct {
+	registry discovery.Registry
+	zone     ZonePreference
+}
+
+func (b *resolverBuilder) Scheme() string {
+	return Scheme
+}
+
+func (b *resolverBuilder) Build(target resolver.Target, cc resolver.ClientConn, _ resolver.BuildOptions) (resolver.Resolver, error) {
+	serviceName := target.Endpoint()
+	if serviceName == "" {
+		return nil, errors.New("registry target is missing a service name")
+	}
+	filter := map[string]string{}
+	for k, v := range target.URL.Query() {
+		filter[k] = v[0]
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	updates, err := b.registry.Watch(ctx, serviceName)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	r := &registryResolver{cc: cc, filter: filter, zone: b.zone, cancel: cancel, done: make(chan struct{})}
+	go r.watch(updates)
+	return r, nil
+}
+
+type registryResolver struct {
+	cc     resolver.ClientConn
+	filter map[string]string
+	zone   ZonePreference
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// watch pushes address updates to the client connection until they stop.
+func (r *registryResolver) watch(updates <-chan []discovery.Instance) {
+	defer close(r.done)
+	for instances := range updates {
+		instances = discovery.Filter(instances, r.filter)
+		instances = discovery.PreferZone(instances, r.zone.Zone, r.zone.MinInstances)
+		if len(instances) == 0 {
+			r.cc.ReportError(discovery.ErrNotFound)
+			continue
+		}
+		state := resolver.State{Addresses: make([]resolver.Address, 0, len(instances))}
+		for _, i := range instances {
+			state.Addresses = append(state.Addresses, resolver.Address{Addr: i.HostPort})
+		}
+		r.cc.UpdateState(state)
+	}
+}
+
+// ResolveNow is a no-op; address changes are pushed as soon as the registry
+// reports them.
+func (r *registryResolver) ResolveNow(resolver.ResolveNowOptions) {}
+
+// Close stops watching the registry.
+func (r *registryResolver) Close() {
+	r.cancel()
+	<-r.done
+}
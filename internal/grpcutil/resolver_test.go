@@ -0,0 +1,103 @@
+package grpcutil
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/Aditya-Chowdhary/micro-movies/pkg/discovery"
+	"github.com/Aditya-Chowdhary/micro-movies/pkg/discovery/memory"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/resolver"
+)
+
+// testClientConn records the state pushed by a resolver.
+type testClientConn struct {
+	resolver.ClientConn
+	states chan resolver.State
+	errs   chan error
+}
+
+func (cc *testClientConn) UpdateState(s resolver.State) error {
+	cc.states <- s
+	return nil
+}
+
+func (cc *testClientConn) ReportError(err error) {
+	cc.errs <- err
+}
+
+func addrsOf(s resolver.State) []string {
+	var res []string
+	for _, a := range s.Addresses {
+		res = append(res, a.Addr)
+	}
+	return res
+}
+
+func TestResolver(t *testing.T) {
+	ctx := context.Background()
+	registry := memory.NewRegistry()
+	defer registry.Close()
+	require.NoError(t, registry.Register(ctx, "a", "svc", "localhost:1", nil))
+
+	cc := &testClientConn{states: make(chan resolver.State, 10), errs: make(chan error, 10)}
+	b := NewResolverBuilder(registry, ZonePreference{})
+	assert.Equal(t, Scheme, b.Scheme())
+	r, err := b.Build(resolver.Target{URL: url.URL{Scheme: Scheme, Path: "/svc"}}, cc, resolver.BuildOptions{})
+	require.NoError(t, err)
+	defer r.Close()
+
+	assert.Equal(t, []string{"localhost:1"}, addrsOf(<-cc.states))
+
+	require.NoError(t, registry.Register(ctx, "b", "svc", "localhost:2", nil))
+	assert.Equal(t, []string{"localhost:1", "localhost:2"}, addrsOf(<-cc.states))
+
+	require.NoError(t, registry.Deregister(ctx, "a", "svc"))
+	assert.Equal(t, []string{"localhost:2"}, addrsOf(<-cc.states))
+	require.NoError(t, registry.Deregister(ctx, "b", "svc"))
+	assert.ErrorIs(t, <-cc.errs, discovery.ErrNotFound)
+
+	require.NoError(t, registry.Register(ctx, "b", "svc", "localhost:2", nil))
+	assert.Equal(t, []string{"localhost:2"}, addrsOf(<-cc.states), "addresses are pushed again after an error")
+}
+
+func TestResolverFilter(t *testing.T) {
+	ctx := context.Background()
+	registry := memory.NewRegistry()
+	defer registry.Close()
+	require.NoError(t, registry.Register(ctx, "a", "svc", "localhost:1", map[string]string{"version": "v1"}))
+	require.NoError(t, registry.Register(ctx, "b", "svc", "localhost:2", map[string]string{"version": "v2"}))
+
+	target, err := url.Parse(Target("svc", map[string]string{"version": "v2"}))
+	require.NoError(t, err)
+	cc := &testClientConn{states: make(chan resolver.State, 10), errs: make(chan error, 10)}
+	r, err := NewResolverBuilder(registry, ZonePreference{}).Build(resolver.Target{URL: *target}, cc, resolver.BuildOptions{})
+	require.NoError(t, err)
+	defer r.Close()
+
+	assert.Equal(t, []string{"localhost:2"}, addrsOf(<-cc.states))
+	require.NoError(t, registry.Deregister(ctx, "b", "svc"))
+	assert.ErrorIs(t, <-cc.errs, discovery.ErrNotFound)
+}
+
+func TestResolverZonePreference(t *testing.T) {
+	ctx := context.Background()
+	registry := memory.NewRegistry()
+	defer registry.Close()
+	require.NoError(t, registry.Register(ctx, "a1", "svc", "localhost:1", map[string]string{discovery.MetadataZone: "a"}))
+	require.NoError(t, registry.Register(ctx, "a2", "svc", "localhost:2", map[string]string{discovery.MetadataZone: "a"}))
+	require.NoError(t, registry.Register(ctx, "b1", "svc", "localhost:3", map[string]string{discovery.MetadataZone: "b"}))
+
+	cc := &testClientConn{states: make(chan resolver.State, 10), errs: make(chan error, 10)}
+	b := NewResolverBuilder(registry, ZonePreference{Zone: "a", MinInstances: 2})
+	r, err := b.Build(resolver.Target{URL: url.URL{Scheme: Scheme, Path: "/svc"}}, cc, resolver.BuildOptions{})
+	require.NoError(t, err)
+	defer r.Close()
+
+	assert.Equal(t, []string{"localhost:1", "localhost:2"}, addrsOf(<-cc.states))
+	require.NoError(t, registry.Deregister(ctx, "a2", "svc"))
+	assert.Equal(t, []string{"localhost:1", "localhost:3"}, addrsOf(<-cc.states), "calls spill over when the zone lacks capacity")
+}
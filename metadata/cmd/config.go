@@ -1,9 +1,20 @@
 package main
 
+import (
+	"time"
+
+	"github.com/Aditya-Chowdhary/micro-movies/pkg/discovery/setup"
+	"github.com/Aditya-Chowdhary/micro-movies/pkg/sqldb"
+)
+
 type config struct {
 	API        apiConfig        `yaml:"api"`
 	Jaeger     jaegerConfig     `yaml:"jaeger"`
 	Prometheus prometheusConfig `yaml:"prometheus"`
+	Registry   setup.Config     `yaml:"registry"`
+	Discovery  discoveryConfig  `yaml:"discovery"`
+	Repository repositoryConfig `yaml:"repository"`
+	Retention  retentionConfig  `yaml:"retention"`
 }
 
 type apiConfig struct {
@@ -16,3 +27,29 @@ type jaegerConfig struct {
 type prometheusConfig struct {
 	MetricsPort int `yaml:"metricsPort"`
 }
+
+type retentionConfig struct {
+	// Window is how long deleted metadata stays restorable before it is purged.
+	Window time.Duration `yaml:"window"`
+	// SweepInterval is how often deleted metadata past the window is purged.
+	SweepInterval time.Duration `yaml:"sweepInterval"`
+}
+
+type repositoryConfig struct {
+	// Driver selects the repository implementation: memory, sqlite or mysql.
+	Driver string `yaml:"driver"`
+	// Migrate applies pending schema migrations at startup.
+	Migrate bool               `yaml:"migrate"`
+	MySQL   sqldb.Config       `yaml:"mysql"`
+	SQLite  sqldb.SQLiteConfig `yaml:"sqlite"`
+}
+
+type discoveryConfig struct {
+	// InstanceID pins the ID the instance registers with. Defaults to an ID
+	// generated from the hostname, the port and a random suffix.
+	InstanceID string `yaml:"instanceID"`
+	// Zone is the zone the instance runs in, advertised with its registration.
+	Zone string `yaml:"zone"`
+	// Metadata is advertised with the service registration, e.g. its version.
+	Metadata map[string]string `yaml:"metadata"`
+}
@@ -3,6 +3,8 @@ package metadata
 import (
 	"context"
 	"errors"
+	"fmt"
+	"time"
 
 	"github.com/Aditya-Chowdhary/micro-movies/metadata/internal/repository"
 	"github.com/Aditya-Chowdhary/micro-movies/metadata/pkg/model"
@@ -10,27 +12,187 @@ import (
 
 var ErrNotFound = errors.New("not found")
 
+// ErrInvalid is returned when metadata fails validation. The wrapped
+// *validate.Error lists the violated fields.
+var ErrInvalid = errors.New("invalid metadata")
+
+// ErrNotDeleted is returned when restoring metadata that is not deleted.
+var ErrNotDeleted = errors.New("metadata is not deleted")
+
+// ErrDeleted is returned when writing soft-deleted metadata, which must be
+// restored first.
+var ErrDeleted = errors.New("metadata is deleted")
+
+// DefaultRetention is how long soft-deleted metadata stays restorable by default.
+const DefaultRetention = 30 * 24 * time.Hour
+
 type metadataRepository interface {
 	Get(ctx context.Context, id string) (*model.Metadata, error)
-	Put(ctx context.Context, id string, m *model.Metadata) error
+	List(ctx context.Context) ([]*model.Metadata, error)
+	Put(ctx context.Context, id string, m *model.Metadata, rev *model.Revision) error
+	ListRevisions(ctx context.Context, id string) ([]*model.Revision, error)
+	GetRevision(ctx context.Context, id string, version int64) (*model.Revision, error)
+	Purge(ctx context.Context, deletedBefore time.Time) (int, error)
 }
 
 type Controller struct {
-	repo metadataRepository
+	repo      metadataRepository
+	retention time.Duration
 }
 
-func New(repo metadataRepository) *Controller {
-	return &Controller{repo}
+// New creates a metadata controller. Soft-deleted metadata can be restored
+// for the retention window, after which it is eligible for purging.
+func New(repo metadataRepository, retention time.Duration) *Controller {
+	return &Controller{repo, retention}
 }
 
-func (c *Controller) Get(ctx context.Context, id string) (*model.Metadata, error) {
+// Get returns movie metadata by id, hiding soft-deleted metadata. When locales
+// are given, ordered most preferred first, the metadata is localized to the best
+// matching language.
+func (c *Controller) Get(ctx context.Context, id string, locales ...string) (*model.Metadata, error) {
 	res, err := c.repo.Get(ctx, id)
+	if err != nil && errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	if res.DeletedAt != nil {
+		return nil, ErrNotFound
+	}
+	return localize(res, locales), nil
+}
+
+// GetAt returns movie metadata as it was after the given revision was written,
+// localized like Get. Like Get, it hides soft-deleted metadata.
+func (c *Controller) GetAt(ctx context.Context, id string, version int64, locales ...string) (*model.Metadata, error) {
+	if _, err := c.Get(ctx, id); err != nil {
+		return nil, err
+	}
+	rev, err := c.repo.GetRevision(ctx, id, version)
+	if err != nil && errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	m := rev.Metadata
+	return localize(&m, locales), nil
+}
+
+func localize(m *model.Metadata, locales []string) *model.Metadata {
+	if len(locales) == 0 {
+		return m
+	}
+	return m.Localize(locales)
+}
+
+// List returns all movie metadata ordered by movie id, including soft-deleted
+// metadata only if includeDeleted is set.
+func (c *Controller) List(ctx context.Context, includeDeleted bool) ([]*model.Metadata, error) {
+	all, err := c.repo.List(ctx)
+	if err != nil || includeDeleted {
+		return all, err
+	}
+	res := make([]*model.Metadata, 0, len(all))
+	for _, m := range all {
+		if m.DeletedAt == nil {
+			res = append(res, m)
+		}
+	}
+	return res, nil
+}
+
+// Put writes movie metadata and records the write as a new revision by author.
+// It returns the recorded revision. Put never changes whether metadata is
+// deleted: writing soft-deleted metadata yields ErrDeleted until it is restored.
+func (c *Controller) Put(ctx context.Context, m *model.Metadata, author string) (*model.Revision, error) {
+	if err := m.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalid, err)
+	}
+	prev, err := c.repo.Get(ctx, m.ID)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return nil, err
+	}
+	if prev != nil && prev.DeletedAt != nil {
+		return nil, ErrDeleted
+	}
+	next := *m
+	next.DeletedAt = nil
+	return c.write(ctx, &next, author)
+}
+
+// Delete soft-deletes movie metadata. It stays restorable for the retention window.
+func (c *Controller) Delete(ctx context.Context, id string, author string) (*model.Revision, error) {
+	prev, err := c.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	next := *prev
+	now := time.Now().UTC()
+	next.DeletedAt = &now
+	return c.write(ctx, &next, author)
+}
+
+// Restore undeletes soft-deleted movie metadata. Metadata deleted longer than
+// the retention window ago is treated as purged and yields ErrNotFound.
+func (c *Controller) Restore(ctx context.Context, id string, author string) (*model.Revision, error) {
+	prev, err := c.repo.Get(ctx, id)
+	if err != nil && errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	if prev.DeletedAt == nil {
+		return nil, ErrNotDeleted
+	}
+	if time.Since(*prev.DeletedAt) > c.retention {
+		return nil, ErrNotFound
+	}
+	next := *prev
+	next.DeletedAt = nil
+	return c.write(ctx, &next, author)
+}
+
+// Purge permanently removes metadata, and its history, deleted longer than the
+// retention window ago. It returns the number of movies purged.
+func (c *Controller) Purge(ctx context.Context) (int, error) {
+	return c.repo.Purge(ctx, time.Now().UTC().Add(-c.retention))
+}
+
+// write stores next and records it as a revision by author. The repository
+// computes the changes of the revision against what it stores when writing,
+// so that they always match the write.
+func (c *Controller) write(ctx context.Context, next *model.Metadata, author string) (*model.Revision, error) {
+	rev := &model.Revision{
+		MovieID:   next.ID,
+		Author:    author,
+		CreatedAt: time.Now().UTC(),
+		Metadata:  *next,
+	}
+	if err := c.repo.Put(ctx, next.ID, next, rev); err != nil {
+		return nil, err
+	}
+	return rev, nil
+}
+
+// ListRevisions returns the revision history of movie metadata, oldest first.
+// Like Get, it hides soft-deleted metadata.
+func (c *Controller) ListRevisions(ctx context.Context, id string) ([]*model.Revision, error) {
+	if _, err := c.Get(ctx, id); err != nil {
+		return nil, err
+	}
+	revs, err := c.repo.ListRevisions(ctx, id)
 	if err != nil && errors.Is(err, repository.ErrNotFound) {
 		return nil, ErrNotFound
 	}
-	return res, err
+	return revs, err
 }
 
-func (c *Controller) Put(ctx context.Context, m *model.Metadata) error {
-	return c.repo.Put(ctx, m.ID, m)
+// Revert restores movie metadata to its state as of the given revision.
+// The revert is itself recorded as a new revision, so history is never rewritten.
+func (c *Controller) Revert(ctx context.Context, id string, version int64, author string) (*model.Revision, error) {
+	m, err := c.GetAt(ctx, id, version)
+	if err != nil {
+		return nil, err
+	}
+	return c.Put(ctx, m, author)
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/Aditya-Chowdhary/micro-movies/metadata/internal/repository"
 	"github.com/Aditya-Chowdhary/micro-movies/metadata/pkg/model"
@@ -43,7 +44,7 @@ func TestController(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			defer ctrl.Finish()
 			repoMock := gen.NewMockmetadataRepository(ctrl)
-			c := New(repoMock)
+			c := New(repoMock, DefaultRetention)
 			ctx := context.Background()
 			id := "id"
 			repoMock.EXPECT().Get(ctx, id).Return(tt.expRepoRes, tt.expRepoErr)
@@ -53,3 +54,124 @@ func TestController(t *testing.T) {
 		})
 	}
 }
+
+func TestControllerRevert(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repoMock := gen.NewMockmetadataRepository(ctrl)
+	c := New(repoMock, DefaultRetention)
+	ctx := context.Background()
+	id := "id"
+
+	old := model.Metadata{ID: id, Title: "Old title", Director: "D"}
+	current := &model.Metadata{ID: id, Title: "New title", Director: "D"}
+	repoMock.EXPECT().GetRevision(ctx, id, int64(1)).Return(&model.Revision{MovieID: id, Version: 1, Metadata: old}, nil)
+	repoMock.EXPECT().Get(ctx, id).Return(current, nil).Times(2)
+	repoMock.EXPECT().Put(ctx, id, &old, gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ string, _ *model.Metadata, rev *model.Revision) error {
+			rev.Version = 3
+			rev.Changes = []model.FieldChange{{Field: "title", OldValue: "New title", NewValue: "Old title"}}
+			return nil
+		})
+
+	rev, err := c.Revert(ctx, id, 1, "editor")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), rev.Version)
+	assert.Equal(t, "editor", rev.Author)
+	assert.Equal(t, old, rev.Metadata)
+	assert.Equal(t, []model.FieldChange{{Field: "title", OldValue: "New title", NewValue: "Old title"}}, rev.Changes)
+}
+
+func TestControllerRestore(t *testing.T) {
+	recent := time.Now().UTC().Add(-time.Hour)
+	expired := time.Now().UTC().Add(-2 * DefaultRetention)
+	testCases := []struct {
+		desc       string
+		expRepoRes *model.Metadata
+		wantErr    error
+	}{
+		{
+			desc:       "not deleted",
+			expRepoRes: &model.Metadata{ID: "id", Title: "T"},
+			wantErr:    ErrNotDeleted,
+		},
+		{
+			desc:       "past retention",
+			expRepoRes: &model.Metadata{ID: "id", Title: "T", DeletedAt: &expired},
+			wantErr:    ErrNotFound,
+		},
+		{
+			desc:       "success",
+			expRepoRes: &model.Metadata{ID: "id", Title: "T", DeletedAt: &recent},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repoMock := gen.NewMockmetadataRepository(ctrl)
+			c := New(repoMock, DefaultRetention)
+			ctx := context.Background()
+			repoMock.EXPECT().Get(ctx, "id").Return(tc.expRepoRes, nil)
+			if tc.wantErr == nil {
+				repoMock.EXPECT().Put(ctx, "id", &model.Metadata{ID: "id", Title: "T"}, gomock.Any()).Return(nil)
+			}
+			_, err := c.Restore(ctx, "id", "editor")
+			assert.Equal(t, tc.wantErr, err, tc.desc)
+		})
+	}
+}
+
+func TestControllerDeleted(t *testing.T) {
+	deletedAt := time.Now().UTC().Add(-time.Hour)
+	deleted := &model.Metadata{ID: "id", Title: "T", DeletedAt: &deletedAt}
+	ctx := context.Background()
+	testCases := []struct {
+		desc    string
+		call    func(c *Controller) error
+		wantErr error
+	}{
+		{
+			desc: "put",
+			call: func(c *Controller) error {
+				_, err := c.Put(ctx, &model.Metadata{ID: "id", Title: "New"}, "editor")
+				return err
+			},
+			wantErr: ErrDeleted,
+		},
+		{
+			desc: "get at revision",
+			call: func(c *Controller) error {
+				_, err := c.GetAt(ctx, "id", 1)
+				return err
+			},
+			wantErr: ErrNotFound,
+		},
+		{
+			desc: "list revisions",
+			call: func(c *Controller) error {
+				_, err := c.ListRevisions(ctx, "id")
+				return err
+			},
+			wantErr: ErrNotFound,
+		},
+		{
+			desc: "revert",
+			call: func(c *Controller) error {
+				_, err := c.Revert(ctx, "id", 1, "editor")
+				return err
+			},
+			wantErr: ErrNotFound,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repoMock := gen.NewMockmetadataRepository(ctrl)
+			repoMock.EXPECT().Get(ctx, "id").Return(deleted, nil)
+			err := tc.call(New(repoMock, DefaultRetention))
+			assert.ErrorIs(t, err, tc.wantErr)
+		})
+	}
+}
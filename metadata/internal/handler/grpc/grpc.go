@@ -3,11 +3,14 @@ package grpc
 import (
 	"context"
 	"errors"
+	"io"
 
 	"github.com/Aditya-Chowdhary/micro-movies/gen"
 	"github.com/Aditya-Chowdhary/micro-movies/metadata/internal/controller/metadata"
 	"github.com/Aditya-Chowdhary/micro-movies/metadata/pkg/model"
+	"github.com/Aditya-Chowdhary/micro-movies/pkg/validate"
 
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
@@ -28,7 +31,13 @@ func (h *Handler) GetMetadata(ctx context.Context, req *gen.GetMetadataRequest)
 	if req == nil || req.MovieId == "" {
 		return nil, status.Errorf(codes.InvalidArgument, "nil req or empty id")
 	}
-	m, err := h.ctrl.Get(ctx, req.MovieId)
+	var m *model.Metadata
+	var err error
+	if req.Revision > 0 {
+		m, err = h.ctrl.GetAt(ctx, req.MovieId, req.Revision, req.Locales...)
+	} else {
+		m, err = h.ctrl.Get(ctx, req.MovieId, req.Locales...)
+	}
 	if err != nil && errors.Is(err, metadata.ErrNotFound) {
 		return nil, status.Errorf(codes.NotFound, err.Error())
 	} else if err != nil {
@@ -44,8 +53,136 @@ func (h *Handler) PutMetadata(ctx context.Context, req *gen.PutMetadataRequest)
 	if req == nil || req.Metadata == nil {
 		return nil, status.Errorf(codes.InvalidArgument, "nil req or metadata")
 	}
-	if err := h.ctrl.Put(ctx, model.MetadataFromProto(req.Metadata)); err != nil {
+	rev, err := h.ctrl.Put(ctx, model.MetadataFromProto(req.Metadata), req.Author)
+	if err != nil && errors.Is(err, metadata.ErrInvalid) {
+		return nil, invalidArgument(err)
+	} else if err != nil && errors.Is(err, metadata.ErrDeleted) {
+		return nil, status.Errorf(codes.FailedPrecondition, err.Error())
+	} else if err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+	return &gen.PutMetadataResponse{Revision: rev.Version}, nil
+}
+
+// ListMetadataRevisions returns the revision history of movie metadata.
+func (h *Handler) ListMetadataRevisions(ctx context.Context, req *gen.ListMetadataRevisionsRequest) (*gen.ListMetadataRevisionsResponse, error) {
+	if req == nil || req.MovieId == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "nil req or empty id")
+	}
+	revs, err := h.ctrl.ListRevisions(ctx, req.MovieId)
+	if err != nil && errors.Is(err, metadata.ErrNotFound) {
+		return nil, status.Errorf(codes.NotFound, err.Error())
+	} else if err != nil {
 		return nil, status.Errorf(codes.Internal, err.Error())
 	}
-	return &gen.PutMetadataResponse{}, nil
+	res := &gen.ListMetadataRevisionsResponse{}
+	for _, r := range revs {
+		res.Revisions = append(res.Revisions, model.RevisionToProto(r))
+	}
+	return res, nil
+}
+
+// RevertMetadata restores movie metadata to a previous revision.
+func (h *Handler) RevertMetadata(ctx context.Context, req *gen.RevertMetadataRequest) (*gen.RevertMetadataResponse, error) {
+	if req == nil || req.MovieId == "" || req.Revision <= 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "nil req, empty id or invalid revision")
+	}
+	rev, err := h.ctrl.Revert(ctx, req.MovieId, req.Revision, req.Author)
+	if err != nil && errors.Is(err, metadata.ErrNotFound) {
+		return nil, status.Errorf(codes.NotFound, err.Error())
+	} else if err != nil && errors.Is(err, metadata.ErrInvalid) {
+		return nil, invalidArgument(err)
+	} else if err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+	return &gen.RevertMetadataResponse{Revision: model.RevisionToProto(rev)}, nil
+}
+
+// ImportMetadata writes a stream of movie metadata. Rows failing to be written are
+// reported in the response and do not abort the rest of the import.
+func (h *Handler) ImportMetadata(stream gen.MetadataService_ImportMetadataServer) error {
+	res := &gen.ImportMetadataResponse{}
+	for row := int64(1); ; row++ {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			return stream.SendAndClose(res)
+		} else if err != nil {
+			return err
+		}
+		if req.Metadata == nil {
+			res.Errors = append(res.Errors, &gen.ImportError{Row: row, Message: "nil metadata"})
+			continue
+		}
+		if _, err := h.ctrl.Put(stream.Context(), model.MetadataFromProto(req.Metadata), req.Author); err != nil {
+			res.Errors = append(res.Errors, &gen.ImportError{Row: row, MovieId: req.Metadata.Id, Message: err.Error()})
+			continue
+		}
+		res.Imported++
+	}
+}
+
+// ExportMetadata streams all movie metadata ordered by movie id. Soft-deleted
+// metadata is only included when requested.
+func (h *Handler) ExportMetadata(req *gen.ExportMetadataRequest, stream gen.MetadataService_ExportMetadataServer) error {
+	all, err := h.ctrl.List(stream.Context(), req.IncludeDeleted)
+	if err != nil {
+		return status.Errorf(codes.Internal, err.Error())
+	}
+	for _, m := range all {
+		if err := stream.Send(&gen.ExportMetadataResponse{Metadata: model.MetadataToProto(m)}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteMetadata soft-deletes movie metadata.
+func (h *Handler) DeleteMetadata(ctx context.Context, req *gen.DeleteMetadataRequest) (*gen.DeleteMetadataResponse, error) {
+	if req == nil || req.MovieId == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "nil req or empty id")
+	}
+	rev, err := h.ctrl.Delete(ctx, req.MovieId, req.Author)
+	if err != nil && errors.Is(err, metadata.ErrNotFound) {
+		return nil, status.Errorf(codes.NotFound, err.Error())
+	} else if err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+	return &gen.DeleteMetadataResponse{Revision: model.RevisionToProto(rev)}, nil
+}
+
+// RestoreMetadata restores soft-deleted movie metadata within the retention window.
+func (h *Handler) RestoreMetadata(ctx context.Context, req *gen.RestoreMetadataRequest) (*gen.RestoreMetadataResponse, error) {
+	if req == nil || req.MovieId == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "nil req or empty id")
+	}
+	rev, err := h.ctrl.Restore(ctx, req.MovieId, req.Author)
+	if err != nil && errors.Is(err, metadata.ErrNotFound) {
+		return nil, status.Errorf(codes.NotFound, err.Error())
+	} else if err != nil && errors.Is(err, metadata.ErrNotDeleted) {
+		return nil, status.Errorf(codes.FailedPrecondition, err.Error())
+	} else if err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+	return &gen.RestoreMetadataResponse{Revision: model.RevisionToProto(rev)}, nil
+}
+
+// invalidArgument converts a validation failure into an InvalidArgument status
+// carrying the violated fields as BadRequest error details.
+func invalidArgument(err error) error {
+	st := status.New(codes.InvalidArgument, err.Error())
+	var verr *validate.Error
+	if !errors.As(err, &verr) {
+		return st.Err()
+	}
+	br := &errdetails.BadRequest{}
+	for _, v := range verr.Violations {
+		br.FieldViolations = append(br.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       v.Field,
+			Description: v.Description,
+		})
+	}
+	if detailed, derr := st.WithDetails(br); derr == nil {
+		st = detailed
+	}
+	return st.Err()
 }
@@ -0,0 +1,77 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Aditya-Chowdhary/micro-movies/gen"
+	"github.com/Aditya-Chowdhary/micro-movies/metadata/internal/controller/metadata"
+	"github.com/Aditya-Chowdhary/micro-movies/metadata/internal/repository/memory"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestPutMetadata(t *testing.T) {
+	tests := []struct {
+		desc           string
+		req            *gen.PutMetadataRequest
+		wantCode       codes.Code
+		wantViolations []*errdetails.BadRequest_FieldViolation
+	}{
+		{
+			desc:     "valid",
+			req:      &gen.PutMetadataRequest{Metadata: &gen.Metadata{Id: "1", Title: "One", Language: "en"}},
+			wantCode: codes.OK,
+		},
+		{
+			desc:     "nil metadata",
+			req:      &gen.PutMetadataRequest{},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			desc:     "missing title and malformed language",
+			req:      &gen.PutMetadataRequest{Metadata: &gen.Metadata{Id: "1", Language: "not a tag"}},
+			wantCode: codes.InvalidArgument,
+			wantViolations: []*errdetails.BadRequest_FieldViolation{
+				{Field: "title", Description: "must not be empty"},
+				{Field: "language", Description: "must be a BCP-47 language tag"},
+			},
+		},
+		{
+			desc: "invalid translation",
+			req: &gen.PutMetadataRequest{Metadata: &gen.Metadata{
+				Id:           "1",
+				Title:        "One",
+				Translations: map[string]*gen.Translation{"fr": {}},
+			}},
+			wantCode: codes.InvalidArgument,
+			wantViolations: []*errdetails.BadRequest_FieldViolation{
+				{Field: "translations[fr].title", Description: "must not be empty"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			h := New(metadata.New(memory.New(), metadata.DefaultRetention))
+			_, err := h.PutMetadata(context.Background(), tt.req)
+			st := status.Convert(err)
+			assert.Equal(t, tt.wantCode, st.Code())
+
+			var violations []*errdetails.BadRequest_FieldViolation
+			for _, d := range st.Details() {
+				br, ok := d.(*errdetails.BadRequest)
+				require.True(t, ok, "unexpected detail %T", d)
+				violations = append(violations, br.FieldViolations...)
+			}
+			require.Len(t, violations, len(tt.wantViolations))
+			for i, want := range tt.wantViolations {
+				assert.True(t, proto.Equal(want, violations[i]), "violation %d: got %v, want %v", i, violations[i], want)
+			}
+		})
+	}
+}
@@ -5,9 +5,11 @@ import (
 	"errors"
 	"log"
 	"net/http"
+	"strconv"
 
 	"github.com/Aditya-Chowdhary/micro-movies/metadata/internal/controller/metadata"
-	"github.com/Aditya-Chowdhary/micro-movies/metadata/internal/repository"
+	"github.com/Aditya-Chowdhary/micro-movies/metadata/pkg/model"
+	"github.com/Aditya-Chowdhary/micro-movies/pkg/validate"
 )
 
 type Handler struct {
@@ -25,8 +27,20 @@ func (h *Handler) GetMetadata(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	ctx := r.Context()
-	m, err := h.ctrl.Get(ctx, id)
-	if err != nil && errors.Is(err, repository.ErrNotFound) {
+	locales := model.ParseAcceptLanguage(r.Header.Get("Accept-Language"))
+	var m *model.Metadata
+	var err error
+	if v := r.FormValue("revision"); v != "" {
+		revision, perr := strconv.ParseInt(v, 10, 64)
+		if perr != nil || revision <= 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		m, err = h.ctrl.GetAt(ctx, id, revision, locales...)
+	} else {
+		m, err = h.ctrl.Get(ctx, id, locales...)
+	}
+	if err != nil && errors.Is(err, metadata.ErrNotFound) {
 		w.WriteHeader(http.StatusNotFound)
 		return
 	} else if err != nil {
@@ -39,3 +53,33 @@ func (h *Handler) GetMetadata(w http.ResponseWriter, r *http.Request) {
 		log.Printf("Response encode error: %v\n", err)
 	}
 }
+
+// PutMetadata handles PUT /metadata requests with a JSON metadata body.
+func (h *Handler) PutMetadata(w http.ResponseWriter, r *http.Request) {
+	var m model.Metadata
+	if err := json.NewDecoder(r.Body).Decode(&m); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	rev, err := h.ctrl.Put(r.Context(), &m, r.FormValue("author"))
+	var verr *validate.Error
+	if err != nil && errors.As(err, &verr) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		if err := json.NewEncoder(w).Encode(map[string][]validate.Violation{"violations": verr.Violations}); err != nil {
+			log.Printf("Response encode error: %v\n", err)
+		}
+		return
+	} else if err != nil && errors.Is(err, metadata.ErrDeleted) {
+		w.WriteHeader(http.StatusConflict)
+		return
+	} else if err != nil {
+		log.Printf("Repository put error: %v\n", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(rev); err != nil {
+		log.Printf("Response encode error: %v\n", err)
+	}
+}
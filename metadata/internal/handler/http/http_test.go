@@ -0,0 +1,96 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Aditya-Chowdhary/micro-movies/metadata/internal/controller/metadata"
+	"github.com/Aditya-Chowdhary/micro-movies/metadata/internal/repository/memory"
+	"github.com/Aditya-Chowdhary/micro-movies/metadata/pkg/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetMetadata(t *testing.T) {
+	ctx := context.Background()
+	ctrl := metadata.New(memory.New(), metadata.DefaultRetention)
+	_, err := ctrl.Put(ctx, &model.Metadata{ID: "1", Title: "One"}, "author")
+	require.NoError(t, err)
+	_, err = ctrl.Put(ctx, &model.Metadata{ID: "2", Title: "Two"}, "author")
+	require.NoError(t, err)
+	_, err = ctrl.Delete(ctx, "2", "author")
+	require.NoError(t, err)
+	h := New(ctrl)
+
+	tests := []struct {
+		desc     string
+		target   string
+		wantCode int
+	}{
+		{desc: "found", target: "/metadata?id=1", wantCode: http.StatusOK},
+		{desc: "found revision", target: "/metadata?id=1&revision=1", wantCode: http.StatusOK},
+		{desc: "missing id", target: "/metadata", wantCode: http.StatusBadRequest},
+		{desc: "invalid revision", target: "/metadata?id=1&revision=x", wantCode: http.StatusBadRequest},
+		{desc: "unknown movie", target: "/metadata?id=3", wantCode: http.StatusNotFound},
+		{desc: "deleted movie", target: "/metadata?id=2", wantCode: http.StatusNotFound},
+		{desc: "unknown revision", target: "/metadata?id=1&revision=5", wantCode: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			h.GetMetadata(w, httptest.NewRequest(http.MethodGet, tt.target, nil))
+			assert.Equal(t, tt.wantCode, w.Code)
+		})
+	}
+}
+
+func TestPutMetadata(t *testing.T) {
+	tests := []struct {
+		desc     string
+		body     string
+		wantCode int
+		wantBody string
+	}{
+		{
+			desc:     "valid",
+			body:     `{"id": "1", "title": "One", "language": "en"}`,
+			wantCode: http.StatusOK,
+		},
+		{
+			desc:     "malformed body",
+			body:     `{`,
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			desc:     "missing title and malformed language",
+			body:     `{"id": "1", "language": "not a tag"}`,
+			wantCode: http.StatusBadRequest,
+			wantBody: `{"violations": [
+				{"field": "title", "description": "must not be empty"},
+				{"field": "language", "description": "must be a BCP-47 language tag"}
+			]}`,
+		},
+		{
+			desc:     "invalid translation",
+			body:     `{"id": "1", "title": "One", "translations": {"fr": {"title": ""}}}`,
+			wantCode: http.StatusBadRequest,
+			wantBody: `{"violations": [{"field": "translations[fr].title", "description": "must not be empty"}]}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			h := New(metadata.New(memory.New(), metadata.DefaultRetention))
+			w := httptest.NewRecorder()
+			h.PutMetadata(w, httptest.NewRequest(http.MethodPut, "/metadata?author=author", strings.NewReader(tt.body)))
+			assert.Equal(t, tt.wantCode, w.Code)
+			if tt.wantBody != "" {
+				assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+				assert.JSONEq(t, tt.wantBody, w.Body.String())
+			}
+		})
+	}
+}
@@ -2,7 +2,9 @@ package memory
 
 import (
 	"context"
+	"sort"
 	"sync"
+	"time"
 
 	"github.com/Aditya-Chowdhary/micro-movies/metadata/internal/repository"
 	"github.com/Aditya-Chowdhary/micro-movies/metadata/pkg/model"
@@ -14,12 +16,14 @@ const tracerID = "metadata-repository-memory"
 
 type Repository struct {
 	sync.RWMutex
-	data map[string]*model.Metadata
+	data      map[string]*model.Metadata
+	revisions map[string][]*model.Revision
 }
 
 func New() *Repository {
 	return &Repository{
-		data: map[string]*model.Metadata{},
+		data:      map[string]*model.Metadata{},
+		revisions: map[string][]*model.Revision{},
 	}
 }
 
@@ -37,11 +41,82 @@ func (r *Repository) Get(ctx context.Context, id string) (*model.Metadata, error
 	return m, nil
 }
 
-func (r *Repository) Put(ctx context.Context, id string, metadata *model.Metadata) error {
+// List returns all movie metadata ordered by movie id.
+func (r *Repository) List(ctx context.Context) ([]*model.Metadata, error) {
+	_, span := otel.Tracer(tracerID).Start(ctx, "Repository/List")
+	defer span.End()
+
+	r.RLock()
+	defer r.RUnlock()
+
+	res := make([]*model.Metadata, 0, len(r.data))
+	for _, m := range r.data {
+		res = append(res, m)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].ID < res[j].ID })
+	return res, nil
+}
+
+// Put stores metadata and appends rev to its history, assigning the revision
+// its version and its changes against the stored metadata.
+func (r *Repository) Put(ctx context.Context, id string, metadata *model.Metadata, rev *model.Revision) error {
 	_, span := otel.Tracer(tracerID).Start(ctx, "Repository/Put")
 	defer span.End()
 	r.Lock()
 	defer r.Unlock()
+	rev.Changes = model.Diff(r.data[id], metadata)
 	r.data[id] = metadata
+	rev.Version = int64(len(r.revisions[id]) + 1)
+	r.revisions[id] = append(r.revisions[id], rev)
 	return nil
 }
+
+// ListRevisions returns all revisions of movie metadata, oldest first.
+func (r *Repository) ListRevisions(ctx context.Context, id string) ([]*model.Revision, error) {
+	_, span := otel.Tracer(tracerID).Start(ctx, "Repository/ListRevisions")
+	defer span.End()
+
+	r.RLock()
+	defer r.RUnlock()
+
+	revs, ok := r.revisions[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return append([]*model.Revision(nil), revs...), nil
+}
+
+// GetRevision returns a single revision of movie metadata.
+func (r *Repository) GetRevision(ctx context.Context, id string, version int64) (*model.Revision, error) {
+	_, span := otel.Tracer(tracerID).Start(ctx, "Repository/GetRevision")
+	defer span.End()
+
+	r.RLock()
+	defer r.RUnlock()
+
+	revs := r.revisions[id]
+	if version < 1 || version > int64(len(revs)) {
+		return nil, repository.ErrNotFound
+	}
+	return revs[version-1], nil
+}
+
+// Purge removes metadata and its revisions deleted before deletedBefore and
+// returns the number of movies removed.
+func (r *Repository) Purge(ctx context.Context, deletedBefore time.Time) (int, error) {
+	_, span := otel.Tracer(tracerID).Start(ctx, "Repository/Purge")
+	defer span.End()
+
+	r.Lock()
+	defer r.Unlock()
+
+	n := 0
+	for id, m := range r.data {
+		if m.DeletedAt != nil && m.DeletedAt.Before(deletedBefore) {
+			delete(r.data, id)
+			delete(r.revisions, id)
+			n++
+		}
+	}
+	return n, nil
+}
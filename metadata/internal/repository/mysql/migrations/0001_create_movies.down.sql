@@ -0,0 +1,2 @@
+DROP TABLE movie_revisions;
+DROP TABLE movies;
@@ -0,0 +1,53 @@
+-- Uses IF NOT EXISTS so databases created before migrations existed can adopt
+-- them. Their tables may predate translations and soft deletion, so the
+-- missing columns are added below.
+CREATE TABLE IF NOT EXISTS movies (
+    id VARCHAR(255),
+    title VARCHAR(255),
+    description TEXT,
+    director VARCHAR(255),
+    language VARCHAR(35),
+    translations JSON,
+    deleted_at DATETIME(6)
+);
+
+CREATE TABLE IF NOT EXISTS movie_revisions (
+    movie_id VARCHAR(255) NOT NULL,
+    version BIGINT NOT NULL,
+    author VARCHAR(255),
+    created_at DATETIME(6) NOT NULL,
+    title VARCHAR(255),
+    description TEXT,
+    director VARCHAR(255),
+    language VARCHAR(35),
+    translations JSON,
+    deleted_at DATETIME(6),
+    changes JSON,
+    PRIMARY KEY (movie_id, version)
+);
+
+-- MySQL has no ADD COLUMN IF NOT EXISTS, so each table is altered through a
+-- prepared statement adding only the columns it lacks.
+SELECT CONCAT_WS(', ',
+    IF(SUM(column_name = 'language') = 0, 'ADD COLUMN language VARCHAR(35)', NULL),
+    IF(SUM(column_name = 'translations') = 0, 'ADD COLUMN translations JSON', NULL),
+    IF(SUM(column_name = 'deleted_at') = 0, 'ADD COLUMN deleted_at DATETIME(6)', NULL))
+FROM information_schema.columns
+WHERE table_schema = DATABASE() AND table_name = 'movies'
+INTO @columns;
+SET @alter = IF(@columns = '', 'DO 0', CONCAT('ALTER TABLE movies ', @columns));
+PREPARE alter_movies FROM @alter;
+EXECUTE alter_movies;
+DEALLOCATE PREPARE alter_movies;
+
+SELECT CONCAT_WS(', ',
+    IF(SUM(column_name = 'language') = 0, 'ADD COLUMN language VARCHAR(35) AFTER director', NULL),
+    IF(SUM(column_name = 'translations') = 0, 'ADD COLUMN translations JSON AFTER language', NULL),
+    IF(SUM(column_name = 'deleted_at') = 0, 'ADD COLUMN deleted_at DATETIME(6) AFTER translations', NULL))
+FROM information_schema.columns
+WHERE table_schema = DATABASE() AND table_name = 'movie_revisions'
+INTO @columns;
+SET @alter = IF(@columns = '', 'DO 0', CONCAT('ALTER TABLE movie_revisions ', @columns));
+PREPARE alter_revisions FROM @alter;
+EXECUTE alter_revisions;
+DEALLOCATE PREPARE alter_revisions;
@@ -0,0 +1,4 @@
+ALTER TABLE movies
+    DROP INDEX idx_movies_deleted_at,
+    DROP PRIMARY KEY,
+    MODIFY id VARCHAR(255);
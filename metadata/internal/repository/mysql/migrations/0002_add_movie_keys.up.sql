@@ -0,0 +1,16 @@
+-- Metadata used to be inserted without a key, so ids may be missing or
+-- duplicated. Rows without an id were never readable.
+DELETE FROM movies WHERE id IS NULL;
+
+-- Copy the movies into a keyed table, keeping the first row of each id, which
+-- is the one reads returned.
+DROP TABLE IF EXISTS movies_keyed;
+CREATE TABLE movies_keyed LIKE movies;
+ALTER TABLE movies_keyed
+    MODIFY id VARCHAR(255) NOT NULL,
+    ADD PRIMARY KEY (id),
+    ADD INDEX idx_movies_deleted_at (deleted_at);
+INSERT INTO movies_keyed SELECT * FROM movies
+    ON DUPLICATE KEY UPDATE movies_keyed.id = movies_keyed.id;
+DROP TABLE movies;
+RENAME TABLE movies_keyed TO movies;
@@ -3,11 +3,11 @@ package mysql
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"time"
 
 	"github.com/Aditya-Chowdhary/micro-movies/metadata/internal/repository"
 	"github.com/Aditya-Chowdhary/micro-movies/metadata/pkg/model"
-
-	_ "github.com/go-sql-driver/mysql"
 )
 
 // Repository defines a MYSQL-based movie metadata repository
@@ -15,39 +15,228 @@ type Repository struct {
 	db *sql.DB
 }
 
-// New creates a new MYSQL-based repository
-func New() (*Repository, error) {
-	db, err := sql.Open("mysql", "root:password@/movieexample")
+// New creates a new MYSQL-based repository backed by db.
+// Use sqldb.Open to obtain a configured connection pool.
+func New(db *sql.DB) *Repository {
+	return &Repository{db}
+}
+
+// Get retrieves movie metadata by movie id
+func (r *Repository) Get(ctx context.Context, id string) (*model.Metadata, error) {
+	query := `SELECT id, title, description, director, language, translations, deleted_at FROM movies WHERE id = ?`
+	m, err := scanMetadata(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, repository.ErrNotFound
+	}
+	return m, err
+}
+
+// List retrieves all movie metadata ordered by movie id
+func (r *Repository) List(ctx context.Context) ([]*model.Metadata, error) {
+	query := `SELECT id, title, description, director, language, translations, deleted_at FROM movies ORDER BY id`
+
+	rows, err := r.db.QueryContext(ctx, query)
 	if err != nil {
 		return nil, err
 	}
-	return &Repository{db}, nil
+	defer rows.Close()
+
+	var res []*model.Metadata
+	for rows.Next() {
+		m, err := scanMetadata(rows)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, m)
+	}
+	return res, rows.Err()
 }
 
-// Get retrieves movie metadata by movie id
-func (r *Repository) Get(ctx context.Context, id string) (*model.Metadata, error) {
-	var title, description, director string
-	query := `SELECT title, description, director FROM movies WHERE id = ?`
-	row := r.db.QueryRowContext(ctx, query, id)
-	if err := row.Scan(&title, &description, &director); err != nil {
-		if err == sql.ErrNoRows {
-			return nil, repository.ErrNotFound
+// Put adds movie metadata for a given movie id and records rev as its next
+// revision. The changes of rev are computed against the stored metadata in the
+// same transaction as the write.
+func (r *Repository) Put(ctx context.Context, id string, metadata *model.Metadata, rev *model.Revision) error {
+	translations, err := json.Marshal(metadata.Translations)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Claim the movie row before reading it. Locking a missing row only takes
+	// a gap lock, which concurrent first writes of the same movie all acquire
+	// before deadlocking on their inserts. The upsert takes an exclusive lock
+	// on the row either way and reports one affected row only when it created
+	// the placeholder, i.e. when there is no previous metadata.
+	query := `INSERT INTO movies (id) VALUES (?) ON DUPLICATE KEY UPDATE id = id`
+	res, err := tx.ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+	created, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	var prev *model.Metadata
+	if created == 0 {
+		query = `SELECT id, title, description, director, language, translations, deleted_at FROM movies WHERE id = ? FOR UPDATE`
+		if prev, err = scanMetadata(tx.QueryRowContext(ctx, query, id)); err != nil {
+			return err
+		}
+	}
+	diff := model.Diff(prev, metadata)
+	changes, err := json.Marshal(diff)
+	if err != nil {
+		return err
+	}
+
+	query = `INSERT INTO movies (id, title, description, director, language, translations, deleted_at)
+	VALUES (?, ?, ?, ?, ?, ?, ?)
+	ON DUPLICATE KEY UPDATE title = VALUES(title), description = VALUES(description), director = VALUES(director),
+	language = VALUES(language), translations = VALUES(translations), deleted_at = VALUES(deleted_at)`
+	if _, err := tx.ExecContext(ctx, query, id, metadata.Title, metadata.Description, metadata.Director,
+		metadata.Language, translations, metadata.DeletedAt); err != nil {
+		return err
+	}
+
+	var version int64
+	query = `SELECT COALESCE(MAX(version), 0) + 1 FROM movie_revisions WHERE movie_id = ? FOR UPDATE`
+	if err := tx.QueryRowContext(ctx, query, id).Scan(&version); err != nil {
+		return err
+	}
+
+	query = `INSERT INTO movie_revisions (movie_id, version, author, created_at, title, description, director, language, translations, deleted_at, changes)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	if _, err := tx.ExecContext(ctx, query, id, version, rev.Author, rev.CreatedAt,
+		metadata.Title, metadata.Description, metadata.Director, metadata.Language, translations, metadata.DeletedAt, changes); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	rev.Version = version
+	rev.Changes = diff
+	return nil
+}
+
+// ListRevisions retrieves all revisions of movie metadata, oldest first
+func (r *Repository) ListRevisions(ctx context.Context, id string) ([]*model.Revision, error) {
+	query := `SELECT version, author, created_at, title, description, director, language, translations, deleted_at, changes
+	FROM movie_revisions WHERE movie_id = ? ORDER BY version`
+
+	rows, err := r.db.QueryContext(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []*model.Revision
+	for rows.Next() {
+		rev, err := scanRevision(rows, id)
+		if err != nil {
+			return nil, err
 		}
+		res = append(res, rev)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(res) == 0 {
+		return nil, repository.ErrNotFound
+	}
+	return res, nil
+}
+
+// GetRevision retrieves a single revision of movie metadata
+func (r *Repository) GetRevision(ctx context.Context, id string, version int64) (*model.Revision, error) {
+	query := `SELECT version, author, created_at, title, description, director, language, translations, deleted_at, changes
+	FROM movie_revisions WHERE movie_id = ? AND version = ?`
+
+	rev, err := scanRevision(r.db.QueryRowContext(ctx, query, id, version), id)
+	if err == sql.ErrNoRows {
+		return nil, repository.ErrNotFound
+	}
+	return rev, err
+}
+
+// Purge removes metadata and its revisions deleted before deletedBefore and
+// returns the number of movies removed
+func (r *Repository) Purge(ctx context.Context, deletedBefore time.Time) (int, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	query := `DELETE movie_revisions FROM movie_revisions JOIN movies ON movies.id = movie_revisions.movie_id
+	WHERE movies.deleted_at < ?`
+	if _, err := tx.ExecContext(ctx, query, deletedBefore); err != nil {
+		return 0, err
+	}
+	res, err := tx.ExecContext(ctx, `DELETE FROM movies WHERE deleted_at < ?`, deletedBefore)
+	if err != nil {
+		return 0, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return int(n), tx.Commit()
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanMetadata(row scanner) (*model.Metadata, error) {
+	m := &model.Metadata{}
+	var language sql.NullString
+	var translations []byte
+	var deletedAt sql.NullTime
+	if err := row.Scan(&m.ID, &m.Title, &m.Description, &m.Director, &language, &translations, &deletedAt); err != nil {
+		return nil, err
+	}
+	m.Language = language.String
+	if deletedAt.Valid {
+		m.DeletedAt = &deletedAt.Time
+	}
+	if err := unmarshalNullable(translations, &m.Translations); err != nil {
 		return nil, err
 	}
-	return &model.Metadata{
-		ID:          id,
-		Title:       title,
-		Description: description,
-		Director:    director,
-	}, nil
+	return m, nil
 }
 
-// Put adds movie metadata for a given movie id
-func (r *Repository) Put(ctx context.Context, id string, metadata *model.Metadata) error {
-	query := `INSERT INTO MOVIES (id, title, description, director)
-	VALUES (?, ?, ?, ?)`
+func scanRevision(row scanner, id string) (*model.Revision, error) {
+	rev := &model.Revision{MovieID: id, Metadata: model.Metadata{ID: id}}
+	var language sql.NullString
+	var translations, changes []byte
+	var deletedAt sql.NullTime
+	if err := row.Scan(&rev.Version, &rev.Author, &rev.CreatedAt,
+		&rev.Metadata.Title, &rev.Metadata.Description, &rev.Metadata.Director,
+		&language, &translations, &deletedAt, &changes); err != nil {
+		return nil, err
+	}
+	rev.Metadata.Language = language.String
+	if deletedAt.Valid {
+		rev.Metadata.DeletedAt = &deletedAt.Time
+	}
+	if err := unmarshalNullable(translations, &rev.Metadata.Translations); err != nil {
+		return nil, err
+	}
+	if err := unmarshalNullable(changes, &rev.Changes); err != nil {
+		return nil, err
+	}
+	return rev, nil
+}
 
-	_, err := r.db.ExecContext(ctx, query, id, metadata.Title, metadata.Description, metadata.Director)
-	return err
+// unmarshalNullable decodes a JSON column, leaving v untouched when the column is NULL.
+func unmarshalNullable(data []byte, v any) error {
+	if data == nil {
+		return nil
+	}
+	return json.Unmarshal(data, v)
 }
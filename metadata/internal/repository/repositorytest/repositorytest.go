@@ -0,0 +1,229 @@
+// Package repositorytest provides a conformance suite that every metadata
+// repository implementation must pass.
+package repositorytest
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Aditya-Chowdhary/micro-movies/metadata/internal/repository"
+	"github.com/Aditya-Chowdhary/micro-movies/metadata/pkg/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Repository is the metadata repository behaviour under test.
+type Repository interface {
+	Get(ctx context.Context, id string) (*model.Metadata, error)
+	List(ctx context.Context) ([]*model.Metadata, error)
+	Put(ctx context.Context, id string, m *model.Metadata, rev *model.Revision) error
+	ListRevisions(ctx context.Context, id string) ([]*model.Revision, error)
+	GetRevision(ctx context.Context, id string, version int64) (*model.Revision, error)
+	Purge(ctx context.Context, deletedBefore time.Time) (int, error)
+}
+
+// Run runs the conformance suite. newRepo must return an empty repository
+// for every call.
+func Run(t *testing.T, newRepo func(t *testing.T) Repository) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, r Repository)
+	}{
+		{"NotFound", testNotFound},
+		{"PutGet", testPutGet},
+		{"Overwrite", testOverwrite},
+		{"List", testList},
+		{"Revisions", testRevisions},
+		{"Changes", testChanges},
+		{"Purge", testPurge},
+		{"Concurrency", testConcurrency},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.fn(t, newRepo(t))
+		})
+	}
+}
+
+// baseTime is truncated to microseconds, the finest precision every backend stores.
+var baseTime = time.Date(2024, 1, 2, 3, 4, 5, 6000, time.UTC)
+
+func put(t *testing.T, r Repository, m *model.Metadata, at time.Time) *model.Revision {
+	t.Helper()
+	rev := &model.Revision{
+		MovieID:   m.ID,
+		Author:    "author",
+		CreatedAt: at,
+		Metadata:  *m,
+	}
+	require.NoError(t, r.Put(context.Background(), m.ID, m, rev))
+	return rev
+}
+
+func testNotFound(t *testing.T, r Repository) {
+	ctx := context.Background()
+	_, err := r.Get(ctx, "missing")
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+	_, err = r.ListRevisions(ctx, "missing")
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+	_, err = r.GetRevision(ctx, "missing", 1)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+
+	all, err := r.List(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, all)
+}
+
+func testPutGet(t *testing.T, r Repository) {
+	m := &model.Metadata{
+		ID:           "1",
+		Title:        "Title",
+		Description:  "Description",
+		Director:     "Director",
+		Language:     "en",
+		Translations: map[string]model.Translation{"fr": {Title: "Titre", Description: "La description"}},
+	}
+	rev := put(t, r, m, baseTime)
+	assert.Equal(t, int64(1), rev.Version)
+
+	got, err := r.Get(context.Background(), "1")
+	require.NoError(t, err)
+	assert.Equal(t, m, got)
+}
+
+func testOverwrite(t *testing.T, r Repository) {
+	ctx := context.Background()
+	first := &model.Metadata{ID: "1", Title: "First", Translations: map[string]model.Translation{"fr": {Title: "Premier"}}}
+	put(t, r, first, baseTime)
+	second := &model.Metadata{ID: "1", Title: "Second", Director: "Director"}
+	rev := put(t, r, second, baseTime.Add(time.Second))
+	assert.Equal(t, int64(2), rev.Version)
+
+	got, err := r.Get(ctx, "1")
+	require.NoError(t, err)
+	assert.Equal(t, second, got)
+
+	all, err := r.List(ctx)
+	require.NoError(t, err)
+	assert.Len(t, all, 1)
+}
+
+func testList(t *testing.T, r Repository) {
+	for _, id := range []string{"b", "c", "a"} {
+		put(t, r, &model.Metadata{ID: id, Title: "Title " + id}, baseTime)
+	}
+	all, err := r.List(context.Background())
+	require.NoError(t, err)
+	ids := make([]string, 0, len(all))
+	for _, m := range all {
+		ids = append(ids, m.ID)
+	}
+	assert.Equal(t, []string{"a", "b", "c"}, ids)
+}
+
+func testRevisions(t *testing.T, r Repository) {
+	ctx := context.Background()
+	first := &model.Metadata{ID: "1", Title: "First"}
+	rev1 := put(t, r, first, baseTime)
+	deletedAt := baseTime.Add(time.Hour)
+	second := &model.Metadata{ID: "1", Title: "First", DeletedAt: &deletedAt}
+	rev2 := put(t, r, second, baseTime.Add(time.Hour))
+
+	revs, err := r.ListRevisions(ctx, "1")
+	require.NoError(t, err)
+	assert.Equal(t, []*model.Revision{rev1, rev2}, revs)
+
+	got, err := r.GetRevision(ctx, "1", 2)
+	require.NoError(t, err)
+	assert.Equal(t, rev2, got)
+
+	_, err = r.GetRevision(ctx, "1", 3)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+}
+
+func testChanges(t *testing.T, r Repository) {
+	first := &model.Metadata{ID: "1", Title: "First", Director: "Director"}
+	rev := put(t, r, first, baseTime)
+	assert.Equal(t, model.Diff(nil, first), rev.Changes, "changes of new metadata are against empty metadata")
+
+	second := &model.Metadata{ID: "1", Title: "Second", Director: "Director"}
+	rev = &model.Revision{
+		MovieID:   "1",
+		CreatedAt: baseTime,
+		Changes:   []model.FieldChange{{Field: "director", OldValue: "stale", NewValue: "stale"}},
+		Metadata:  *second,
+	}
+	require.NoError(t, r.Put(context.Background(), "1", second, rev))
+	want := []model.FieldChange{{Field: "title", OldValue: "First", NewValue: "Second"}}
+	assert.Equal(t, want, rev.Changes, "changes are computed against the stored metadata")
+
+	got, err := r.GetRevision(context.Background(), "1", 2)
+	require.NoError(t, err)
+	assert.Equal(t, want, got.Changes)
+}
+
+func testPurge(t *testing.T, r Repository) {
+	ctx := context.Background()
+	deletedAt := baseTime.Add(time.Hour)
+	put(t, r, &model.Metadata{ID: "deleted", Title: "Deleted", DeletedAt: &deletedAt}, baseTime)
+	put(t, r, &model.Metadata{ID: "kept", Title: "Kept"}, baseTime)
+
+	n, err := r.Purge(ctx, deletedAt)
+	require.NoError(t, err)
+	assert.Equal(t, 0, n, "metadata deleted at the cutoff is kept")
+
+	n, err = r.Purge(ctx, deletedAt.Add(time.Second))
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	_, err = r.Get(ctx, "deleted")
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+	_, err = r.ListRevisions(ctx, "deleted")
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+	_, err = r.Get(ctx, "kept")
+	assert.NoError(t, err)
+}
+
+func testConcurrency(t *testing.T, r Repository) {
+	ctx := context.Background()
+	const writers = 10
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 2*writers)
+	for i := 0; i < writers; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			m := &model.Metadata{ID: "shared", Title: fmt.Sprintf("Title %d", i)}
+			errs <- r.Put(ctx, m.ID, m, &model.Revision{MovieID: m.ID, CreatedAt: baseTime, Metadata: *m})
+		}(i)
+		go func(i int) {
+			defer wg.Done()
+			m := &model.Metadata{ID: fmt.Sprintf("own-%d", i), Title: "Title"}
+			errs <- r.Put(ctx, m.ID, m, &model.Revision{MovieID: m.ID, CreatedAt: baseTime, Metadata: *m})
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		require.NoError(t, err)
+	}
+
+	revs, err := r.ListRevisions(ctx, "shared")
+	require.NoError(t, err)
+	require.Len(t, revs, writers)
+	for i, rev := range revs {
+		assert.Equal(t, int64(i+1), rev.Version)
+	}
+	got, err := r.Get(ctx, "shared")
+	require.NoError(t, err)
+	assert.Equal(t, revs[writers-1].Metadata.Title, got.Title, "latest revision matches stored metadata")
+
+	all, err := r.List(ctx)
+	require.NoError(t, err)
+	assert.Len(t, all, writers+1)
+}
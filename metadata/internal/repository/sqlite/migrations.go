@@ -0,0 +1,28 @@
+package sqlite
+
+import (
+	"database/sql"
+	"embed"
+	"io/fs"
+
+	"github.com/Aditya-Chowdhary/micro-movies/pkg/migrate"
+)
+
+// MigrationTable records the schema migrations applied for the metadata service.
+const MigrationTable = "metadata_schema_migrations"
+
+//go:embed migrations/*.sql
+var migrationFS embed.FS
+
+// NewMigrator creates a migrator for the metadata service schema.
+func NewMigrator(db *sql.DB) (*migrate.Migrator, error) {
+	fsys, err := fs.Sub(migrationFS, "migrations")
+	if err != nil {
+		return nil, err
+	}
+	migrations, err := migrate.Load(fsys)
+	if err != nil {
+		return nil, err
+	}
+	return migrate.New(db, migrate.SQLite, MigrationTable, migrations), nil
+}
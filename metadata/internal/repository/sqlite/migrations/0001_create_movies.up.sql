@@ -0,0 +1,27 @@
+-- Timestamps are stored as Unix nanoseconds so they compare correctly.
+CREATE TABLE movies (
+    id TEXT NOT NULL PRIMARY KEY,
+    title TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    director TEXT NOT NULL DEFAULT '',
+    language TEXT NOT NULL DEFAULT '',
+    translations TEXT,
+    deleted_at INTEGER
+);
+
+CREATE INDEX idx_movies_deleted_at ON movies (deleted_at);
+
+CREATE TABLE movie_revisions (
+    movie_id TEXT NOT NULL,
+    version INTEGER NOT NULL,
+    author TEXT NOT NULL DEFAULT '',
+    created_at INTEGER NOT NULL,
+    title TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    director TEXT NOT NULL DEFAULT '',
+    language TEXT NOT NULL DEFAULT '',
+    translations TEXT,
+    deleted_at INTEGER,
+    changes TEXT,
+    PRIMARY KEY (movie_id, version)
+);
@@ -0,0 +1,242 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/Aditya-Chowdhary/micro-movies/metadata/internal/repository"
+	"github.com/Aditya-Chowdhary/micro-movies/metadata/pkg/model"
+)
+
+// Repository defines a SQLite-based movie metadata repository
+type Repository struct {
+	db *sql.DB
+}
+
+// New creates a new SQLite-based repository backed by db.
+// Use sqldb.OpenSQLite to open the database and NewMigrator to create its schema.
+func New(db *sql.DB) *Repository {
+	return &Repository{db}
+}
+
+// Get retrieves movie metadata by movie id
+func (r *Repository) Get(ctx context.Context, id string) (*model.Metadata, error) {
+	query := `SELECT id, title, description, director, language, translations, deleted_at FROM movies WHERE id = ?`
+	m, err := scanMetadata(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, repository.ErrNotFound
+	}
+	return m, err
+}
+
+// List retrieves all movie metadata ordered by movie id
+func (r *Repository) List(ctx context.Context) ([]*model.Metadata, error) {
+	query := `SELECT id, title, description, director, language, translations, deleted_at FROM movies ORDER BY id`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []*model.Metadata
+	for rows.Next() {
+		m, err := scanMetadata(rows)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, m)
+	}
+	return res, rows.Err()
+}
+
+// Put adds movie metadata for a given movie id and records rev as its next
+// revision. The changes of rev are computed against the stored metadata in the
+// same transaction as the write.
+func (r *Repository) Put(ctx context.Context, id string, metadata *model.Metadata, rev *model.Revision) error {
+	translations, err := marshalText(metadata.Translations)
+	if err != nil {
+		return err
+	}
+	deletedAt := toNullUnix(metadata.DeletedAt)
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `SELECT id, title, description, director, language, translations, deleted_at FROM movies WHERE id = ?`
+	prev, err := scanMetadata(tx.QueryRowContext(ctx, query, id))
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+	diff := model.Diff(prev, metadata)
+	changes, err := marshalText(diff)
+	if err != nil {
+		return err
+	}
+
+	query = `INSERT INTO movies (id, title, description, director, language, translations, deleted_at)
+	VALUES (?, ?, ?, ?, ?, ?, ?)
+	ON CONFLICT (id) DO UPDATE SET title = excluded.title, description = excluded.description, director = excluded.director,
+	language = excluded.language, translations = excluded.translations, deleted_at = excluded.deleted_at`
+	if _, err := tx.ExecContext(ctx, query, id, metadata.Title, metadata.Description, metadata.Director,
+		metadata.Language, translations, deletedAt); err != nil {
+		return err
+	}
+
+	var version int64
+	query = `SELECT COALESCE(MAX(version), 0) + 1 FROM movie_revisions WHERE movie_id = ?`
+	if err := tx.QueryRowContext(ctx, query, id).Scan(&version); err != nil {
+		return err
+	}
+
+	query = `INSERT INTO movie_revisions (movie_id, version, author, created_at, title, description, director, language, translations, deleted_at, changes)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	if _, err := tx.ExecContext(ctx, query, id, version, rev.Author, rev.CreatedAt.UnixNano(),
+		metadata.Title, metadata.Description, metadata.Director, metadata.Language, translations, deletedAt, changes); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	rev.Version = version
+	rev.Changes = diff
+	return nil
+}
+
+// ListRevisions retrieves all revisions of movie metadata, oldest first
+func (r *Repository) ListRevisions(ctx context.Context, id string) ([]*model.Revision, error) {
+	query := `SELECT version, author, created_at, title, description, director, language, translations, deleted_at, changes
+	FROM movie_revisions WHERE movie_id = ? ORDER BY version`
+
+	rows, err := r.db.QueryContext(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []*model.Revision
+	for rows.Next() {
+		rev, err := scanRevision(rows, id)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, rev)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(res) == 0 {
+		return nil, repository.ErrNotFound
+	}
+	return res, nil
+}
+
+// GetRevision retrieves a single revision of movie metadata
+func (r *Repository) GetRevision(ctx context.Context, id string, version int64) (*model.Revision, error) {
+	query := `SELECT version, author, created_at, title, description, director, language, translations, deleted_at, changes
+	FROM movie_revisions WHERE movie_id = ? AND version = ?`
+
+	rev, err := scanRevision(r.db.QueryRowContext(ctx, query, id, version), id)
+	if err == sql.ErrNoRows {
+		return nil, repository.ErrNotFound
+	}
+	return rev, err
+}
+
+// Purge removes metadata and its revisions deleted before deletedBefore and
+// returns the number of movies removed
+func (r *Repository) Purge(ctx context.Context, deletedBefore time.Time) (int, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	before := deletedBefore.UnixNano()
+	query := `DELETE FROM movie_revisions WHERE movie_id IN (SELECT id FROM movies WHERE deleted_at < ?)`
+	if _, err := tx.ExecContext(ctx, query, before); err != nil {
+		return 0, err
+	}
+	res, err := tx.ExecContext(ctx, `DELETE FROM movies WHERE deleted_at < ?`, before)
+	if err != nil {
+		return 0, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return int(n), tx.Commit()
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanMetadata(row scanner) (*model.Metadata, error) {
+	m := &model.Metadata{}
+	var translations sql.NullString
+	var deletedAt sql.NullInt64
+	if err := row.Scan(&m.ID, &m.Title, &m.Description, &m.Director, &m.Language, &translations, &deletedAt); err != nil {
+		return nil, err
+	}
+	m.DeletedAt = fromNullUnix(deletedAt)
+	if err := unmarshalNullable(translations, &m.Translations); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func scanRevision(row scanner, id string) (*model.Revision, error) {
+	rev := &model.Revision{MovieID: id, Metadata: model.Metadata{ID: id}}
+	var createdAt int64
+	var translations, changes sql.NullString
+	var deletedAt sql.NullInt64
+	if err := row.Scan(&rev.Version, &rev.Author, &createdAt,
+		&rev.Metadata.Title, &rev.Metadata.Description, &rev.Metadata.Director,
+		&rev.Metadata.Language, &translations, &deletedAt, &changes); err != nil {
+		return nil, err
+	}
+	rev.CreatedAt = time.Unix(0, createdAt).UTC()
+	rev.Metadata.DeletedAt = fromNullUnix(deletedAt)
+	if err := unmarshalNullable(translations, &rev.Metadata.Translations); err != nil {
+		return nil, err
+	}
+	if err := unmarshalNullable(changes, &rev.Changes); err != nil {
+		return nil, err
+	}
+	return rev, nil
+}
+
+// marshalText encodes v as JSON for a TEXT column.
+func marshalText(v any) (string, error) {
+	b, err := json.Marshal(v)
+	return string(b), err
+}
+
+// unmarshalNullable decodes a JSON column, leaving v untouched when the column is NULL.
+func unmarshalNullable(data sql.NullString, v any) error {
+	if !data.Valid {
+		return nil
+	}
+	return json.Unmarshal([]byte(data.String), v)
+}
+
+func toNullUnix(t *time.Time) sql.NullInt64 {
+	if t == nil {
+		return sql.NullInt64{}
+	}
+	return sql.NullInt64{Int64: t.UnixNano(), Valid: true}
+}
+
+func fromNullUnix(v sql.NullInt64) *time.Time {
+	if !v.Valid {
+		return nil
+	}
+	t := time.Unix(0, v.Int64).UTC()
+	return &t
+}
@@ -0,0 +1,25 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Aditya-Chowdhary/micro-movies/metadata/internal/repository/repositorytest"
+	"github.com/Aditya-Chowdhary/micro-movies/pkg/sqldb"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepository(t *testing.T) {
+	repositorytest.Run(t, func(t *testing.T) repositorytest.Repository {
+		ctx := context.Background()
+		db, err := sqldb.OpenSQLite(ctx, sqldb.SQLiteConfig{Path: ":memory:"})
+		require.NoError(t, err)
+		t.Cleanup(func() { db.Close() })
+		migrator, err := NewMigrator(db)
+		require.NoError(t, err)
+		_, err = migrator.Up(ctx)
+		require.NoError(t, err)
+		return New(db)
+	})
+}
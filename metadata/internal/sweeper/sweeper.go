@@ -0,0 +1,45 @@
+package sweeper
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type purger interface {
+	Purge(ctx context.Context) (int, error)
+}
+
+// Sweeper periodically purges movie metadata soft-deleted longer than the retention window.
+type Sweeper struct {
+	ctrl     purger
+	interval time.Duration
+	logger   *zap.Logger
+}
+
+// New creates a new sweeper purging through ctrl every interval.
+func New(ctrl purger, interval time.Duration, logger *zap.Logger) *Sweeper {
+	return &Sweeper{ctrl, interval, logger}
+}
+
+// Run purges on every tick until ctx is cancelled.
+func (s *Sweeper) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			n, err := s.ctrl.Purge(ctx)
+			if err != nil {
+				s.logger.Error("Failed to purge deleted metadata", zap.Error(err))
+				continue
+			}
+			if n > 0 {
+				s.logger.Info("Purged deleted metadata", zap.Int("count", n))
+			}
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package sweeper
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// fakePurger fails the first purge and counts every purge.
+type fakePurger struct {
+	mu    sync.Mutex
+	calls int
+}
+
+func (p *fakePurger) Purge(ctx context.Context) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.calls++
+	if p.calls == 1 {
+		return 0, errors.New("database unavailable")
+	}
+	return 1, nil
+}
+
+func (p *fakePurger) count() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.calls
+}
+
+func TestSweeper(t *testing.T) {
+	p := &fakePurger{}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		New(p, 5*time.Millisecond, zap.NewNop()).Run(ctx)
+	}()
+
+	require.Eventually(t, func() bool { return p.count() >= 3 }, time.Second, time.Millisecond,
+		"purges keep running after a failure")
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Run did not return after cancellation")
+	}
+	n := p.count()
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, n, p.count(), "no purges after cancellation")
+}
@@ -0,0 +1,69 @@
+package model
+
+import (
+	"sort"
+
+	"golang.org/x/text/language"
+)
+
+// Localize returns a copy of the metadata with its title and description in the
+// language best matching locales, ordered most preferred first. It falls back to
+// the untranslated text when no translation matches, and to the untranslated
+// description when the matching translation has none. The copy carries no
+// translations.
+func (m *Metadata) Localize(locales []string) *Metadata {
+	res := *m
+	res.Translations = nil
+	if len(locales) == 0 || len(m.Translations) == 0 {
+		return &res
+	}
+
+	// The untranslated text comes first so the matcher falls back to it.
+	base := language.Und
+	if m.Language != "" {
+		base = language.Make(m.Language)
+	}
+	supported := []language.Tag{base}
+	keys := make([]string, 0, len(m.Translations))
+	for k := range m.Translations {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		supported = append(supported, language.Make(k))
+	}
+
+	desired := make([]language.Tag, 0, len(locales))
+	for _, l := range locales {
+		if t, err := language.Parse(l); err == nil {
+			desired = append(desired, t)
+		}
+	}
+	_, i, confidence := language.NewMatcher(supported).Match(desired...)
+	if i == 0 || confidence == language.No {
+		return &res
+	}
+	tag := keys[i-1]
+	t := m.Translations[tag]
+	res.Title = t.Title
+	// Descriptions are optional in translations.
+	if t.Description != "" {
+		res.Description = t.Description
+	}
+	res.Language = tag
+	return &res
+}
+
+// ParseAcceptLanguage returns the language tags of an Accept-Language value
+// ordered by preference. Malformed values yield no tags.
+func ParseAcceptLanguage(s string) []string {
+	tags, _, err := language.ParseAcceptLanguage(s)
+	if err != nil {
+		return nil
+	}
+	res := make([]string, 0, len(tags))
+	for _, t := range tags {
+		res = append(res, t.String())
+	}
+	return res
+}
@@ -0,0 +1,50 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocalize(t *testing.T) {
+	m := &Metadata{
+		ID:          "id",
+		Title:       "The Movie",
+		Description: "Description",
+		Language:    "en",
+		Translations: map[string]Translation{
+			"fr":    {Title: "Le Film", Description: "La description"},
+			"pt-BR": {Title: "O Filme"},
+		},
+	}
+	testCases := []struct {
+		desc      string
+		locales   []string
+		wantTitle string
+		wantDesc  string
+		wantLang  string
+	}{
+		{desc: "no locales", wantTitle: "The Movie", wantDesc: "Description", wantLang: "en"},
+		{desc: "exact match", locales: []string{"fr"}, wantTitle: "Le Film", wantDesc: "La description", wantLang: "fr"},
+		{desc: "regional variant", locales: []string{"fr-CA"}, wantTitle: "Le Film", wantDesc: "La description", wantLang: "fr"},
+		{desc: "title-only translation keeps the description", locales: []string{"de", "pt-BR", "fr"}, wantTitle: "O Filme", wantDesc: "Description", wantLang: "pt-BR"},
+		{desc: "base language preferred", locales: []string{"en-GB", "fr"}, wantTitle: "The Movie", wantDesc: "Description", wantLang: "en"},
+		{desc: "fallback", locales: []string{"ja"}, wantTitle: "The Movie", wantDesc: "Description", wantLang: "en"},
+		{desc: "malformed locales ignored", locales: []string{"!!", "fr"}, wantTitle: "Le Film", wantDesc: "La description", wantLang: "fr"},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.desc, func(t *testing.T) {
+			got := m.Localize(tt.locales)
+			assert.Equal(t, tt.wantTitle, got.Title)
+			assert.Equal(t, tt.wantDesc, got.Description)
+			assert.Equal(t, tt.wantLang, got.Language)
+			assert.Nil(t, got.Translations)
+		})
+	}
+	assert.Len(t, m.Translations, 2, "Localize must not modify the receiver")
+}
+
+func TestParseAcceptLanguage(t *testing.T) {
+	assert.Equal(t, []string{"fr-CA", "fr", "en"}, ParseAcceptLanguage("fr-CA, en;q=0.5, fr;q=0.9"))
+	assert.Empty(t, ParseAcceptLanguage(""))
+}
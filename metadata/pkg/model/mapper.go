@@ -1,23 +1,78 @@
 package model
 
-import "github.com/Aditya-Chowdhary/micro-movies/gen"
+import (
+	"time"
+
+	"github.com/Aditya-Chowdhary/micro-movies/gen"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
 
 // MetadataToProto converts a Metadata struct into a generated proto counterpart
 func MetadataToProto(m *Metadata) *gen.Metadata {
+	var translations map[string]*gen.Translation
+	if len(m.Translations) > 0 {
+		translations = make(map[string]*gen.Translation, len(m.Translations))
+		for tag, t := range m.Translations {
+			translations[tag] = &gen.Translation{Title: t.Title, Description: t.Description}
+		}
+	}
+	var deletedAt *timestamppb.Timestamp
+	if m.DeletedAt != nil {
+		deletedAt = timestamppb.New(*m.DeletedAt)
+	}
 	return &gen.Metadata{
-		Id:          m.ID,
-		Title:       m.Title,
-		Description: m.Description,
-		Director:    m.Director,
+		Id:           m.ID,
+		Title:        m.Title,
+		Description:  m.Description,
+		Director:     m.Director,
+		Language:     m.Language,
+		Translations: translations,
+		DeletedAt:    deletedAt,
 	}
 }
 
 // MetadataFromProto converts a generated proto counterpart into a Metadata struct
 func MetadataFromProto(m *gen.Metadata) *Metadata {
+	var translations map[string]Translation
+	if len(m.Translations) > 0 {
+		translations = make(map[string]Translation, len(m.Translations))
+		for tag, t := range m.Translations {
+			translations[tag] = Translation{Title: t.GetTitle(), Description: t.GetDescription()}
+		}
+	}
+	var deletedAt *time.Time
+	if m.DeletedAt != nil {
+		t := m.DeletedAt.AsTime()
+		deletedAt = &t
+	}
 	return &Metadata{
-		ID:          m.Id,
-		Title:       m.Title,
-		Description: m.Description,
-		Director:    m.Director,
+		ID:           m.Id,
+		Title:        m.Title,
+		Description:  m.Description,
+		Director:     m.Director,
+		Language:     m.Language,
+		Translations: translations,
+		DeletedAt:    deletedAt,
+	}
+}
+
+// RevisionToProto converts a Revision struct into a generated proto counterpart
+func RevisionToProto(r *Revision) *gen.MetadataRevision {
+	changes := make([]*gen.FieldChange, 0, len(r.Changes))
+	for _, c := range r.Changes {
+		changes = append(changes, &gen.FieldChange{
+			Field:    c.Field,
+			OldValue: c.OldValue,
+			NewValue: c.NewValue,
+		})
+	}
+	return &gen.MetadataRevision{
+		MovieId:   r.MovieID,
+		Version:   r.Version,
+		Author:    r.Author,
+		CreatedAt: timestamppb.New(r.CreatedAt),
+		Changes:   changes,
+		Metadata:  MetadataToProto(&r.Metadata),
 	}
 }
@@ -1,8 +1,64 @@
 package model
 
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Aditya-Chowdhary/micro-movies/pkg/validate"
+
+	"golang.org/x/text/language"
+)
+
 type Metadata struct {
-	ID          string `json:"id"`
-	Title       string `json:"title"`
-	Description string `json:"description"`
-	Director    string `json:"director"`
+	ID          string `json:"id" validate:"required,max=255"`
+	Title       string `json:"title" validate:"required,max=255"`
+	Description string `json:"description" validate:"max=4000"`
+	Director    string `json:"director" validate:"max=255"`
+	// Language is the BCP-47 tag of Title and Description, empty if unknown.
+	Language string `json:"language,omitempty" validate:"max=35"`
+	// Translations holds localized titles and descriptions keyed by BCP-47 tag.
+	Translations map[string]Translation `json:"translations,omitempty"`
+	// DeletedAt is set when the metadata has been soft-deleted.
+	DeletedAt *time.Time `json:"deletedAt,omitempty"`
+}
+
+// Translation is the localized text of movie metadata in a single language.
+type Translation struct {
+	Title       string `json:"title" validate:"required,max=255"`
+	Description string `json:"description" validate:"max=4000"`
+}
+
+// Validate checks metadata against the rules declared on its fields and
+// verifies that every language tag is well-formed BCP-47.
+func (m *Metadata) Validate() error {
+	var violations []validate.Violation
+	if err := validate.Struct(m); err != nil {
+		var verr *validate.Error
+		if !errors.As(err, &verr) {
+			return err
+		}
+		violations = verr.Violations
+	}
+	if m.Language != "" {
+		if _, err := language.Parse(m.Language); err != nil {
+			violations = append(violations, validate.Violation{Field: "language", Description: "must be a BCP-47 language tag"})
+		}
+	}
+	for tag, t := range m.Translations {
+		field := fmt.Sprintf("translations[%s]", tag)
+		if _, err := language.Parse(tag); err != nil {
+			violations = append(violations, validate.Violation{Field: field, Description: "must be keyed by a BCP-47 language tag"})
+		}
+		var verr *validate.Error
+		if err := validate.Struct(&t); errors.As(err, &verr) {
+			for _, v := range verr.Violations {
+				violations = append(violations, validate.Violation{Field: field + "." + v.Field, Description: v.Description})
+			}
+		}
+	}
+	if len(violations) > 0 {
+		return &validate.Error{Violations: violations}
+	}
+	return nil
 }
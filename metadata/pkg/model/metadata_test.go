@@ -0,0 +1,83 @@
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Aditya-Chowdhary/micro-movies/pkg/validate"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetadataValidate(t *testing.T) {
+	testCases := []struct {
+		desc string
+		m    Metadata
+		want []validate.Violation
+	}{
+		{
+			desc: "valid",
+			m: Metadata{
+				ID:           "1",
+				Title:        "The Movie",
+				Language:     "en-US",
+				Translations: map[string]Translation{"pt-BR": {Title: "O Filme"}},
+			},
+		},
+		{
+			desc: "missing id and title",
+			m:    Metadata{Title: " "},
+			want: []validate.Violation{
+				{Field: "id", Description: "must not be empty"},
+				{Field: "title", Description: "must not be empty"},
+			},
+		},
+		{
+			desc: "too long",
+			m:    Metadata{ID: "1", Title: strings.Repeat("a", 256), Description: strings.Repeat("a", 4001)},
+			want: []validate.Violation{
+				{Field: "title", Description: "must be at most 255 characters"},
+				{Field: "description", Description: "must be at most 4000 characters"},
+			},
+		},
+		{
+			desc: "malformed language",
+			m:    Metadata{ID: "1", Title: "The Movie", Language: "not a tag"},
+			want: []validate.Violation{{Field: "language", Description: "must be a BCP-47 language tag"}},
+		},
+		{
+			desc: "translation keyed by malformed tag",
+			m: Metadata{
+				ID:           "1",
+				Title:        "The Movie",
+				Translations: map[string]Translation{"not a tag": {Title: "Le Film"}},
+			},
+			want: []validate.Violation{{Field: "translations[not a tag]", Description: "must be keyed by a BCP-47 language tag"}},
+		},
+		{
+			desc: "translation without title",
+			m: Metadata{
+				ID:           "1",
+				Title:        "The Movie",
+				Translations: map[string]Translation{"fr": {Description: strings.Repeat("a", 4001)}},
+			},
+			want: []validate.Violation{
+				{Field: "translations[fr].title", Description: "must not be empty"},
+				{Field: "translations[fr].description", Description: "must be at most 4000 characters"},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			err := tc.m.Validate()
+			if tc.want == nil {
+				assert.NoError(t, err)
+				return
+			}
+			var verr *validate.Error
+			require.ErrorAs(t, err, &verr)
+			assert.Equal(t, tc.want, verr.Violations)
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package model
+
+import (
+	"sort"
+	"time"
+)
+
+// Revision is an immutable record of a single write to movie metadata.
+type Revision struct {
+	MovieID   string        `json:"movieId"`
+	Version   int64         `json:"version"`
+	Author    string        `json:"author"`
+	CreatedAt time.Time     `json:"createdAt"`
+	Changes   []FieldChange `json:"changes"`
+	Metadata  Metadata      `json:"metadata"`
+}
+
+// FieldChange describes a single metadata field modified by a revision.
+type FieldChange struct {
+	Field    string `json:"field"`
+	OldValue string `json:"oldValue"`
+	NewValue string `json:"newValue"`
+}
+
+// Diff returns the field changes turning prev into next. A nil prev is treated as empty metadata.
+func Diff(prev, next *Metadata) []FieldChange {
+	if prev == nil {
+		prev = &Metadata{}
+	}
+	var changes []FieldChange
+	add := func(field, oldValue, newValue string) {
+		if oldValue != newValue {
+			changes = append(changes, FieldChange{Field: field, OldValue: oldValue, NewValue: newValue})
+		}
+	}
+	add("title", prev.Title, next.Title)
+	add("description", prev.Description, next.Description)
+	add("director", prev.Director, next.Director)
+	add("language", prev.Language, next.Language)
+	add("deletedAt", formatTime(prev.DeletedAt), formatTime(next.DeletedAt))
+
+	tags := map[string]bool{}
+	for tag := range prev.Translations {
+		tags[tag] = true
+	}
+	for tag := range next.Translations {
+		tags[tag] = true
+	}
+	sorted := make([]string, 0, len(tags))
+	for tag := range tags {
+		sorted = append(sorted, tag)
+	}
+	sort.Strings(sorted)
+	for _, tag := range sorted {
+		p, n := prev.Translations[tag], next.Translations[tag]
+		add("translations["+tag+"].title", p.Title, n.Title)
+		add("translations["+tag+"].description", p.Description, n.Description)
+	}
+	return changes
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
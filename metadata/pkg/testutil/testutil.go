@@ -10,6 +10,6 @@ import (
 // NewTestMetadataGRPCServer creates a new metadata gRPC server for tests
 func NewTestMetadataGRPCServer() gen.MetadataServiceServer {
 	r := memory.New()
-	ctrl := metadata.New(r)
+	ctrl := metadata.New(r, metadata.DefaultRetention)
 	return grpchandler.New(ctrl)
 }
@@ -1,9 +1,30 @@
 package main
 
+import (
+	"os"
+	"time"
+
+	"github.com/Aditya-Chowdhary/micro-movies/movie/internal/gateway/routing"
+	"github.com/Aditya-Chowdhary/micro-movies/pkg/discovery/setup"
+
+	"gopkg.in/yaml.v3"
+)
+
+const configPath = "./configs/base.yaml"
+
 type config struct {
 	API        apiConfig        `yaml:"api"`
 	Jaeger     jaegerConfig     `yaml:"jaeger"`
 	Prometheus prometheusConfig `yaml:"prometheus"`
+	Admin      adminConfig      `yaml:"admin"`
+	Registry   setup.Config     `yaml:"registry"`
+	Discovery  discoveryConfig  `yaml:"discovery"`
+	Cache      cacheConfig      `yaml:"cache"`
+	Stale      staleConfig      `yaml:"stale"`
+	Balancer   balancerConfig   `yaml:"balancer"`
+	// Routing splits calls to downstream services between instance groups.
+	// It is reloaded when the service receives SIGHUP.
+	Routing routing.Rules `yaml:"routing"`
 }
 
 type apiConfig struct {
@@ -16,3 +37,56 @@ type jaegerConfig struct {
 type prometheusConfig struct {
 	MetricsPort int `yaml:"metricsPort"`
 }
+
+type adminConfig struct {
+	// Address is where the admin endpoints, e.g. /cache/invalidate, are
+	// served. It is kept apart from the public metrics port and defaults to
+	// localhost:8094.
+	Address string `yaml:"address"`
+}
+
+type cacheConfig struct {
+	// Size is the maximum number of cached metadata entries. Zero disables the cache.
+	Size        int           `yaml:"size"`
+	TTL         time.Duration `yaml:"ttl"`
+	NegativeTTL time.Duration `yaml:"negativeTTL"`
+}
+
+type staleConfig struct {
+	// MaxAge is how long the last known good details of a movie are served
+	// when downstream calls fail. Defaults to a day.
+	MaxAge time.Duration `yaml:"maxAge"`
+}
+
+type balancerConfig struct {
+	// Policy picks the instance of a downstream service to call: random,
+	// round_robin, least_request or p2c_ewma.
+	Policy string `yaml:"policy"`
+}
+
+type discoveryConfig struct {
+	// InstanceID pins the ID the instance registers with. Defaults to an ID
+	// generated from the hostname, the port and a random suffix.
+	InstanceID string `yaml:"instanceID"`
+	// Zone is the zone the instance runs in, advertised with its registration.
+	Zone string `yaml:"zone"`
+	// Metadata is advertised with the service registration, e.g. its version.
+	Metadata map[string]string `yaml:"metadata"`
+	// Filters restricts calls to instances of a downstream service, keyed by
+	// service name, to those whose metadata matches, e.g. rating: {version: v2}.
+	Filters map[string]map[string]string `yaml:"filters"`
+	// MinZoneInstances is the number of healthy instances of a downstream
+	// service in Zone below which calls spill over to other zones.
+	MinZoneInstances int `yaml:"minZoneInstances"`
+}
+
+func readConfig() (config, error) {
+	var cfg config
+	f, err := os.Open(configPath)
+	if err != nil {
+		return cfg, err
+	}
+	defer f.Close()
+	err = yaml.NewDecoder(f).Decode(&cfg)
+	return cfg, err
+}
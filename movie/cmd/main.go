@@ -3,18 +3,28 @@ package main
 import (
 	"context"
 	"fmt"
+	"maps"
 	"net"
 	"net/http"
 	"os"
+	"os/signal"
+	"sync"
+	"syscall"
 	"time"
 
 	"github.com/Aditya-Chowdhary/micro-movies/gen"
+	"github.com/Aditya-Chowdhary/micro-movies/internal/grpcutil"
 	"github.com/Aditya-Chowdhary/micro-movies/movie/internal/controller/movie"
+	metadatacache "github.com/Aditya-Chowdhary/micro-movies/movie/internal/gateway/metadata/cache"
 	metadatagateway "github.com/Aditya-Chowdhary/micro-movies/movie/internal/gateway/metadata/grpc"
+	"github.com/Aditya-Chowdhary/micro-movies/movie/internal/gateway/picker"
 	ratinggateway "github.com/Aditya-Chowdhary/micro-movies/movie/internal/gateway/rating/grpc"
+	"github.com/Aditya-Chowdhary/micro-movies/movie/internal/gateway/routing"
 	grpchandler "github.com/Aditya-Chowdhary/micro-movies/movie/internal/handler/grpc"
 	"github.com/Aditya-Chowdhary/micro-movies/pkg/discovery"
-	"github.com/Aditya-Chowdhary/micro-movies/pkg/discovery/consul"
+	discoverycache "github.com/Aditya-Chowdhary/micro-movies/pkg/discovery/cache"
+	"github.com/Aditya-Chowdhary/micro-movies/pkg/discovery/setup"
+	"github.com/Aditya-Chowdhary/micro-movies/pkg/health"
 	"github.com/Aditya-Chowdhary/micro-movies/pkg/tracing"
 
 	"github.com/uber-go/tally"
@@ -25,12 +35,15 @@ import (
 	"go.uber.org/zap"
 	"golang.org/x/time/rate"
 	"google.golang.org/grpc"
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/reflection"
-	"gopkg.in/yaml.v3"
 )
 
 const serviceName = "movie"
 
+const defaultAdminAddress = "localhost:8094"
+
 type limiter struct {
 	l *rate.Limiter
 }
@@ -47,13 +60,9 @@ func main() {
 	logger, _ := zap.NewProduction()
 	defer logger.Sync()
 
-	f, err := os.Open("./configs/base.yaml")
+	cfg, err := readConfig()
 	if err != nil {
-		logger.Fatal("Failed to open configuration", zap.Error(err))
-	}
-	var cfg config
-	if err := yaml.NewDecoder(f).Decode(&cfg); err != nil {
-		logger.Fatal("Failed to parse configuration", zap.Error(err))
+		logger.Fatal("Failed to read configuration", zap.Error(err))
 	}
 	port := cfg.API.Port
 
@@ -96,48 +105,136 @@ func main() {
 	}).Counter("service_started")
 	counter.Inc(1)
 
-	// ! Code for using consul service registry
-	registry, err := consul.NewRegistry("localhost:8500")
+	// ! Code for using the configured service registry
+	registry, closeRegistry, err := setup.NewRegistry(cfg.Registry)
 	if err != nil {
-		logger.Fatal("Failed to create consule registry on port 8500: ", zap.Error(err))
+		logger.Fatal("Failed to create service registry", zap.Error(err))
 	}
+	defer closeRegistry()
 
-	instanceID := discovery.GenerateInstanceID(serviceName)
-	if err := registry.Register(ctx, instanceID, serviceName, fmt.Sprintf("localhost:%d", port)); err != nil {
-		logger.Fatal("Failed to generate instanceID for metadata: ", zap.Error(err))
+	hostPort := fmt.Sprintf("localhost:%d", port)
+	instanceID := cfg.Discovery.InstanceID
+	if instanceID == "" {
+		if instanceID, err = discovery.GenerateInstanceID(serviceName, hostPort); err != nil {
+			logger.Fatal("Failed to generate instance ID", zap.Error(err))
+		}
+	}
+	instanceMetadata := map[string]string{discovery.MetadataProtocol: "grpc"}
+	maps.Copy(instanceMetadata, cfg.Discovery.Metadata)
+	if cfg.Discovery.Zone != "" {
+		instanceMetadata[discovery.MetadataZone] = cfg.Discovery.Zone
 	}
 
+	healthServer := grpchealth.NewServer()
+	checker := health.NewChecker(healthServer, health.Options{}, gen.MovieService_ServiceDesc.ServiceName)
+	checker.OnChange = func(err error) {
+		if err != nil {
+			logger.Warn("Service is not ready", zap.Error(err))
+		} else {
+			logger.Info("Service is ready")
+		}
+	}
+	checker.OnDegradedChange = func(err error) {
+		if err != nil {
+			logger.Warn("Service is degraded", zap.Error(err))
+		} else {
+			logger.Info("Service is no longer degraded")
+		}
+	}
+	lifecycle := discovery.NewLifecycle(registry, discovery.Registration{
+		InstanceID:  instanceID,
+		ServiceName: serviceName,
+		HostPort:    hostPort,
+		Metadata:    instanceMetadata,
+	})
+	lifecycle.OnError = func(err error) {
+		logger.Warn("Failed to keep the service registered", zap.Error(err))
+	}
+	lifecycle.Ready = checker.Ready
+	deregistered := make(chan struct{})
 	go func() {
-		for {
-			if err := registry.ReportHealthyState(instanceID, serviceName); err != nil {
-				logger.Error("Failed to report healthy state: " + err.Error())
-			}
-			time.Sleep(1 * time.Second)
+		defer close(deregistered)
+		if err := lifecycle.Run(ctx); err != nil {
+			logger.Error("Failed to deregister the service", zap.Error(err))
 		}
 	}()
-	defer registry.Deregister(ctx, instanceID, serviceName)
+	defer func() {
+		cancel()
+		<-deregistered
+	}()
 
 	// ! Code for using memory service registry
 	// registry := memory.NewRegistry()
+	// defer registry.Close()
 	// ctx := context.Background()
-	// metadatainstanceID := discovery.GenerateInstanceID(serviceName)
-	// if err := registry.Register(ctx, metadatainstanceID, "movie", "localhost:8081"); err != nil {
+	// metadatainstanceID, _ := discovery.GenerateInstanceID("metadata", "localhost:8081")
+	// if err := registry.Register(ctx, metadatainstanceID, "metadata", "localhost:8081", nil); err != nil {
 	// 	panic(err)
 	// }
-	// ratinginstanceID := discovery.GenerateInstanceID(serviceName)
-	// if err := registry.Register(ctx, ratinginstanceID, "movie", "localhost:8082"); err != nil {
+	// ratinginstanceID, _ := discovery.GenerateInstanceID("rating", "localhost:8082")
+	// if err := registry.Register(ctx, ratinginstanceID, "rating", "localhost:8082", nil); err != nil {
 	// 	panic(err)
 	// }
-	// movieinstanceID := discovery.GenerateInstanceID(serviceName)
-	// if err := registry.Register(ctx, movieinstanceID, "movie", "localhost:8083"); err != nil {
+	// movieinstanceID, _ := discovery.GenerateInstanceID(serviceName, "localhost:8083")
+	// if err := registry.Register(ctx, movieinstanceID, "movie", "localhost:8083", nil); err != nil {
 	// 	panic(err)
 	// }
 	// defer registry.Deregister(ctx, movieinstanceID, "movie")
 
-	// ! Unchanged
-	metadataGateway := metadatagateway.New(registry)
-	ratingGateway := ratinggateway.New(registry)
-	ctrl := movie.New(ratingGateway, metadataGateway)
+	policy, err := picker.ParsePolicy(cfg.Balancer.Policy)
+	if err != nil {
+		logger.Fatal("Failed to parse balancer configuration", zap.Error(err))
+	}
+	conns := grpcutil.NewConnManager(registry, grpc.WithDefaultServiceConfig(picker.ServiceConfig(policy)))
+	defer conns.Close()
+	conns.PreferZone(grpcutil.ZonePreference{Zone: cfg.Discovery.Zone, MinInstances: cfg.Discovery.MinZoneInstances})
+	for service, filter := range cfg.Discovery.Filters {
+		conns.SetFilter(service, filter)
+	}
+	// Health checks look instances up on every run, so serve them from
+	// watched snapshots rather than querying the registry each time.
+	lookups := discoverycache.New(registry)
+	defer lookups.Close()
+	// Movie details can be served without ratings, so a rating outage only
+	// degrades the service.
+	checker.Add("metadata", health.Instances(lookups, "metadata", cfg.Discovery.Filters["metadata"]))
+	checker.AddOptional("rating", health.Instances(lookups, "rating", cfg.Discovery.Filters["rating"]))
+	router := routing.New(conns, scope)
+	if err := router.SetRules(cfg.Routing); err != nil {
+		logger.Fatal("Failed to parse routing configuration", zap.Error(err))
+	}
+	go reloadRouting(router, logger)
+	metadataGateway := metadatagateway.New(router)
+	ratingGateway := ratinggateway.New(router)
+	maxStaleAge := cfg.Stale.MaxAge
+	if maxStaleAge == 0 {
+		maxStaleAge = movie.DefaultMaxStaleAge
+	}
+	var ctrl *movie.Controller
+	if cfg.Cache.Size > 0 {
+		metadataCache := metadatacache.New(metadataGateway, metadatacache.Options{
+			Size:        cfg.Cache.Size,
+			TTL:         cfg.Cache.TTL,
+			NegativeTTL: cfg.Cache.NegativeTTL,
+		}, scope)
+		// Invalidation is an admin operation, so it is not served on the
+		// metrics port.
+		adminMux := http.NewServeMux()
+		adminMux.Handle("/cache/invalidate", metadataCache.InvalidateHandler())
+		adminAddr := cfg.Admin.Address
+		if adminAddr == "" {
+			adminAddr = defaultAdminAddress
+		}
+		go func() {
+			if err := http.ListenAndServe(adminAddr, adminMux); err != nil {
+				logger.Fatal("Failed to start the admin handler", zap.Error(err))
+			}
+		}()
+		ctrl = movie.New(ratingGateway, metadataCache, maxStaleAge)
+	} else {
+		ctrl = movie.New(ratingGateway, metadataGateway, maxStaleAge)
+	}
+	defer ctrl.Close()
 	h := grpchandler.New(ctrl)
 
 	lis, err := net.Listen("tcp", fmt.Sprintf("localhost:%v", port))
@@ -152,7 +249,42 @@ func main() {
 	srv := grpc.NewServer(grpc.StatsHandler(otelgrpc.NewServerHandler()))
 	reflection.Register(srv)
 	gen.RegisterMovieServiceServer(srv, h)
+	healthpb.RegisterHealthServer(srv, healthServer)
+	go checker.Run(ctx)
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		s := <-sigChan
+		cancel()
+		logger.Info(fmt.Sprintf("Received signal %v, attempting graceful shutdown\n", s))
+		srv.GracefulStop()
+		logger.Info("Gracefully stopped the gRPC server")
+	}()
 	if err := srv.Serve(lis); err != nil {
 		panic(err)
 	}
+	wg.Wait()
+}
+
+// reloadRouting replaces the routing rules with the configured ones whenever
+// the service receives SIGHUP.
+func reloadRouting(router *routing.Router, logger *zap.Logger) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	for range hup {
+		cfg, err := readConfig()
+		if err != nil {
+			logger.Error("Failed to reload configuration", zap.Error(err))
+			continue
+		}
+		if err := router.SetRules(cfg.Routing); err != nil {
+			logger.Error("Failed to reload routing rules", zap.Error(err))
+			continue
+		}
+		logger.Info("Reloaded routing rules")
+	}
 }
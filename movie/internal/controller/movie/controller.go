@@ -3,7 +3,11 @@ package movie
 import (
 	"context"
 	"errors"
+	"maps"
+	"slices"
+	"strings"
 	"sync"
+	"time"
 
 	metadatamodel "github.com/Aditya-Chowdhary/micro-movies/metadata/pkg/model"
 	"github.com/Aditya-Chowdhary/micro-movies/movie/internal/gateway"
@@ -19,55 +23,220 @@ type ratingGateway interface {
 }
 
 type metadataGateway interface {
-	Get(ctx context.Context, id string) (*metadatamodel.Metadata, error)
+	Get(ctx context.Context, id string, locales ...string) (*metadatamodel.Metadata, error)
 }
 
 // Controller defines a movie service controller
 type Controller struct {
 	ratingGateway   ratingGateway
 	metadataGateway metadataGateway
+
+	maxStaleAge    time.Duration
+	now            func() time.Time
+	refreshBackoff time.Duration
+
+	// closed is cancelled by Close to stop background refreshes.
+	closed    context.Context
+	stop      context.CancelFunc
+	refreshes sync.WaitGroup
+
+	mu         sync.Mutex
+	inflight   map[string]*call
+	lastGood   map[string]*lastGoodDetails
+	refreshing map[string]bool
 }
 
-// New creates a new movie service controller
-func New(ratingGateway ratingGateway, metametadataGateway metadataGateway) *Controller {
+// call is a downstream fetch of movie details shared by concurrent callers.
+type call struct {
+	done    chan struct{}
+	cancel  context.CancelFunc
+	waiters int
+	details *model.MovieDetails
+	err     error
+}
+
+// New creates a new movie service controller. When downstream calls fail, it
+// serves the last known good details for up to maxStaleAge.
+func New(ratingGateway ratingGateway, metametadataGateway metadataGateway, maxStaleAge time.Duration) *Controller {
+	closed, stop := context.WithCancel(context.Background())
 	return &Controller{
-		ratingGateway,
-		metametadataGateway,
+		ratingGateway:   ratingGateway,
+		metadataGateway: metametadataGateway,
+		maxStaleAge:     maxStaleAge,
+		now:             time.Now,
+		refreshBackoff:  defaultRefreshBackoff,
+		closed:          closed,
+		stop:            stop,
+		inflight:        map[string]*call{},
+		lastGood:        map[string]*lastGoodDetails{},
+		refreshing:      map[string]bool{},
+	}
+}
+
+// Get returns the movies details including the aggregated rating and movie metadata.
+// Metadata is localized to the best match of locales, ordered most preferred first.
+//
+// Concurrent calls for the same movie and locales share a single downstream
+// fetch. The fetch is not tied to any one caller: a caller whose context is
+// cancelled returns early while the others keep waiting, and the fetch is only
+// cancelled once every caller has gone.
+//
+// When metadata or ratings cannot be fetched, Get falls back to the last known
+// good details, marking them stale and listing the degraded components, and
+// refreshes them in the background.
+func (c *Controller) Get(ctx context.Context, id string, locales ...string) (*model.MovieDetails, error) {
+	key := id + "\x00" + strings.Join(locales, ",")
+
+	c.mu.Lock()
+	cl, ok := c.inflight[key]
+	if !ok {
+		fetchCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		cl = &call{done: make(chan struct{}), cancel: cancel}
+		c.inflight[key] = cl
+		go func() {
+			cl.details, cl.err = c.fetch(fetchCtx, key, id, locales)
+			cancel()
+			c.forget(key, cl)
+			close(cl.done)
+		}()
+	}
+	cl.waiters++
+	c.mu.Unlock()
+
+	select {
+	case <-cl.done:
+		if cl.err != nil {
+			return nil, cl.err
+		}
+		return copyDetails(cl.details), nil
+	case <-ctx.Done():
+		c.mu.Lock()
+		cl.waiters--
+		if cl.waiters == 0 {
+			cl.cancel()
+			c.forgetLocked(key, cl)
+		}
+		c.mu.Unlock()
+		return nil, ctx.Err()
+	}
+}
+
+// Close stops the background refreshes of last known good details and waits
+// for them to return.
+func (c *Controller) Close() {
+	c.mu.Lock()
+	c.stop()
+	c.mu.Unlock()
+	c.refreshes.Wait()
+}
+
+// copyDetails returns a deep copy of details shared between callers, so that
+// no caller can modify what the others are served.
+func copyDetails(details *model.MovieDetails) *model.MovieDetails {
+	res := *details
+	if details.Rating != nil {
+		rating := *details.Rating
+		res.Rating = &rating
+	}
+	if details.Metadata.DeletedAt != nil {
+		deletedAt := *details.Metadata.DeletedAt
+		res.Metadata.DeletedAt = &deletedAt
 	}
+	res.Metadata.Translations = maps.Clone(details.Metadata.Translations)
+	res.Degraded = slices.Clone(details.Degraded)
+	return &res
 }
 
-// Get returns the movies details including the aggregated rating and movie metadata
-func (c *Controller) Get(ctx context.Context, id string) (*model.MovieDetails, error) {
+// forget removes cl from the in-flight calls unless it has already been replaced.
+func (c *Controller) forget(key string, cl *call) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.forgetLocked(key, cl)
+}
+
+func (c *Controller) forgetLocked(key string, cl *call) {
+	if c.inflight[key] == cl {
+		delete(c.inflight, key)
+	}
+}
+
+func (c *Controller) fetch(ctx context.Context, key string, id string, locales []string) (*model.MovieDetails, error) {
+	res := c.load(ctx, id, locales)
+	if err := ctx.Err(); err != nil {
+		// Every caller has gone; there is nobody to degrade gracefully for.
+		return nil, err
+	}
+	if err := res.metadataErr; err != nil && errors.Is(err, gateway.ErrNotFound) {
+		c.forgetLastGood(key)
+		return nil, ErrNotFound
+	}
+
+	last := c.getLastGood(key)
+	details := &model.MovieDetails{}
+	if res.metadataErr != nil {
+		if last == nil {
+			return nil, res.metadataErr
+		}
+		details.Metadata = last.details.Metadata
+		details.Degraded = append(details.Degraded, model.ComponentMetadata)
+		details.Status.Metadata = model.StatusUnavailable
+	} else {
+		details.Metadata = *res.metadata
+		details.Status.Metadata = model.StatusOK
+	}
+
+	switch err := res.ratingErr; {
+	case err == nil:
+		details.Rating = res.rating
+		details.Status.Rating = model.StatusOK
+	case errors.Is(err, gateway.ErrNotFound):
+		// Proceed. It is ok to not have ratings
+		details.Status.Rating = model.StatusNotFound
+	default:
+		// The rating service is unavailable. Serve the last known rating, if there is one
+		if last != nil {
+			details.Rating = last.details.Rating
+		}
+		details.Degraded = append(details.Degraded, model.ComponentRating)
+		details.Status.Rating = model.StatusUnavailable
+	}
+
+	if len(details.Degraded) == 0 {
+		c.setLastGood(key, details)
+		return details, nil
+	}
+	if last != nil {
+		details.Stale = true
+		details.Age = c.now().Sub(last.fetchedAt)
+	}
+	c.revalidate(key, id, locales)
+	return details, nil
+}
+
+// loadResult holds the outcome of fetching metadata and ratings from downstream.
+type loadResult struct {
+	metadata    *metadatamodel.Metadata
+	metadataErr error
+	rating      *float64
+	ratingErr   error
+}
+
+func (c *Controller) load(ctx context.Context, id string, locales []string) loadResult {
+	var res loadResult
 	var wg sync.WaitGroup
 	wg.Add(2)
-	var metadata *metadatamodel.Metadata
-	var getMetadataErr error
-	var rating float64
-	var getRatingErr error
 	go func() {
 		defer wg.Done()
-		metadata, getMetadataErr = c.metadataGateway.Get(ctx, id)
+		res.metadata, res.metadataErr = c.metadataGateway.Get(ctx, id, locales...)
 	}()
 	go func() {
 		defer wg.Done()
-		rating, getRatingErr = c.ratingGateway.GetAggregatedRating(ctx, ratingmodel.RecordID(id), ratingmodel.RecordTypeMovie)
+		rating, err := c.ratingGateway.GetAggregatedRating(ctx, ratingmodel.RecordID(id), ratingmodel.RecordTypeMovie)
+		if err == nil {
+			res.rating = &rating
+		}
+		res.ratingErr = err
 	}()
 	wg.Wait()
-
-	if err := getMetadataErr; err != nil && errors.Is(err, gateway.ErrNotFound) {
-		return nil, ErrNotFound
-	} else if err != nil {
-		return nil, err
-	}
-
-	details := &model.MovieDetails{Metadata: *metadata}
-	if err := getRatingErr; err != nil && !errors.Is(err, gateway.ErrNotFound) {
-		// Proceed. It is ok to not have ratings
-	} else if err != nil {
-		return nil, err
-	} else {
-		details.Rating = &rating
-	}
-
-	return details, nil
+	return res
 }
@@ -0,0 +1,316 @@
+package movie
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	metadatamodel "github.com/Aditya-Chowdhary/micro-movies/metadata/pkg/model"
+	"github.com/Aditya-Chowdhary/micro-movies/movie/internal/gateway"
+	"github.com/Aditya-Chowdhary/micro-movies/movie/pkg/model"
+	ratingmodel "github.com/Aditya-Chowdhary/micro-movies/rating/pkg/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// blockingGateway serves both gateways, blocking every call until release is closed.
+type blockingGateway struct {
+	release chan struct{}
+	calls   atomic.Int32
+}
+
+func (g *blockingGateway) Get(ctx context.Context, id string, _ ...string) (*metadatamodel.Metadata, error) {
+	g.calls.Add(1)
+	select {
+	case <-g.release:
+		return &metadatamodel.Metadata{ID: id, Title: "Title"}, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (g *blockingGateway) GetAggregatedRating(ctx context.Context, _ ratingmodel.RecordID, _ ratingmodel.RecordType) (float64, error) {
+	select {
+	case <-g.release:
+		return 4.5, nil
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+}
+
+func TestControllerCoalescesConcurrentGets(t *testing.T) {
+	g := &blockingGateway{release: make(chan struct{})}
+	c := New(g, g, DefaultMaxStaleAge)
+
+	// The first caller gives up before the fetch completes; the rest must still get results.
+	firstCtx, cancelFirst := context.WithCancel(context.Background())
+	firstErr := make(chan error, 1)
+	go func() {
+		_, err := c.Get(firstCtx, "1")
+		firstErr <- err
+	}()
+	require.Eventually(t, func() bool { return g.calls.Load() == 1 }, time.Second, time.Millisecond)
+
+	const callers = 10
+	var wg sync.WaitGroup
+	results := make(chan *model.MovieDetails, callers)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			details, err := c.Get(context.Background(), "1")
+			if assert.NoError(t, err) {
+				results <- details
+			}
+		}()
+	}
+	require.Eventually(t, func() bool {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		return c.inflight["1\x00"] != nil && c.inflight["1\x00"].waiters == callers+1
+	}, time.Second, time.Millisecond)
+
+	cancelFirst()
+	assert.ErrorIs(t, <-firstErr, context.Canceled)
+
+	close(g.release)
+	wg.Wait()
+	close(results)
+	assert.Len(t, results, callers)
+	assert.Equal(t, int32(1), g.calls.Load(), "concurrent callers share one downstream fetch")
+	first := <-results
+	*first.Rating = 0
+	for details := range results {
+		assert.Equal(t, 4.5, *details.Rating, "callers do not share the rating")
+	}
+}
+
+func TestControllerCancelsFetchWhenAllCallersLeave(t *testing.T) {
+	g := &blockingGateway{release: make(chan struct{})}
+	c := New(g, g, DefaultMaxStaleAge)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		_, err := c.Get(ctx, "1")
+		done <- err
+	}()
+	require.Eventually(t, func() bool { return g.calls.Load() == 1 }, time.Second, time.Millisecond)
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled)
+
+	// A later caller starts a fresh fetch rather than joining the abandoned one.
+	close(g.release)
+	details, err := c.Get(context.Background(), "1")
+	require.NoError(t, err)
+	assert.Equal(t, "Title", details.Metadata.Title)
+	assert.Equal(t, int32(2), g.calls.Load())
+}
+
+// flakyGateway serves both gateways, failing calls while its errors are set.
+type flakyGateway struct {
+	mu          sync.Mutex
+	title       string
+	rating      float64
+	metadataErr error
+	ratingErr   error
+}
+
+func (g *flakyGateway) set(title string, rating float64, metadataErr, ratingErr error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.title, g.rating, g.metadataErr, g.ratingErr = title, rating, metadataErr, ratingErr
+}
+
+func (g *flakyGateway) Get(_ context.Context, id string, _ ...string) (*metadatamodel.Metadata, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.metadataErr != nil {
+		return nil, g.metadataErr
+	}
+	return &metadatamodel.Metadata{ID: id, Title: g.title}, nil
+}
+
+func (g *flakyGateway) GetAggregatedRating(context.Context, ratingmodel.RecordID, ratingmodel.RecordType) (float64, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.rating, g.ratingErr
+}
+
+func TestControllerServesStaleDetails(t *testing.T) {
+	ctx := context.Background()
+	unavailable := errors.New("unavailable")
+	g := &flakyGateway{}
+	c := New(g, g, DefaultMaxStaleAge)
+	t.Cleanup(c.Close)
+	c.refreshBackoff = time.Millisecond
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	var nowMu sync.Mutex
+	c.now = func() time.Time {
+		nowMu.Lock()
+		defer nowMu.Unlock()
+		return now
+	}
+
+	g.set("", 0, unavailable, nil)
+	_, err := c.Get(ctx, "1")
+	assert.ErrorIs(t, err, unavailable, "no fallback before the first successful fetch")
+
+	g.set("Title", 4, nil, nil)
+	details, err := c.Get(ctx, "1")
+	require.NoError(t, err)
+	assert.False(t, details.Stale)
+
+	nowMu.Lock()
+	now = now.Add(time.Minute)
+	nowMu.Unlock()
+	g.set("", 0, unavailable, unavailable)
+	details, err = c.Get(ctx, "1")
+	require.NoError(t, err)
+	assert.True(t, details.Stale)
+	assert.Equal(t, time.Minute, details.Age)
+	assert.Equal(t, "Title", details.Metadata.Title)
+	assert.Equal(t, 4.0, *details.Rating)
+	assert.Equal(t, []string{model.ComponentMetadata, model.ComponentRating}, details.Degraded)
+	assert.Equal(t, model.Status{Metadata: model.StatusUnavailable, Rating: model.StatusUnavailable}, details.Status)
+
+	// Once downstream recovers, the background refresh updates the fallback.
+	g.set("New title", 5, nil, nil)
+	require.Eventually(t, func() bool {
+		last := c.getLastGood("1\x00")
+		return last != nil && last.details.Metadata.Title == "New title"
+	}, time.Second, time.Millisecond)
+
+	g.set("Ignored", 0, nil, unavailable)
+	details, err = c.Get(ctx, "1")
+	require.NoError(t, err)
+	assert.True(t, details.Stale)
+	assert.Equal(t, "Ignored", details.Metadata.Title, "fresh metadata is served alongside a stale rating")
+	assert.Equal(t, 5.0, *details.Rating)
+	assert.Equal(t, []string{model.ComponentRating}, details.Degraded)
+}
+
+func TestControllerMaxStaleAge(t *testing.T) {
+	ctx := context.Background()
+	unavailable := errors.New("unavailable")
+	g := &flakyGateway{}
+	c := New(g, g, time.Hour)
+	t.Cleanup(c.Close)
+	c.refreshBackoff = time.Hour
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c.now = func() time.Time { return now }
+
+	g.set("Title", 4, nil, nil)
+	_, err := c.Get(ctx, "1")
+	require.NoError(t, err)
+
+	g.set("", 0, unavailable, nil)
+	now = now.Add(time.Hour)
+	details, err := c.Get(ctx, "1")
+	require.NoError(t, err)
+	assert.True(t, details.Stale)
+
+	now = now.Add(time.Second)
+	_, err = c.Get(ctx, "1")
+	assert.ErrorIs(t, err, unavailable, "details older than the max stale age are not served")
+}
+
+func TestControllerCloseStopsRefreshes(t *testing.T) {
+	unavailable := errors.New("unavailable")
+	g := &flakyGateway{}
+	c := New(g, g, DefaultMaxStaleAge)
+	c.refreshBackoff = time.Hour
+
+	g.set("Title", 4, nil, unavailable)
+	_, err := c.Get(context.Background(), "1")
+	require.NoError(t, err)
+	c.mu.Lock()
+	assert.True(t, c.refreshing["1\x00"], "a degraded fetch starts a refresh")
+	c.mu.Unlock()
+
+	closed := make(chan struct{})
+	go func() {
+		c.Close()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+	case <-time.After(time.Second):
+		t.Fatal("Close did not stop the refresh")
+	}
+	c.revalidate("2\x00", "2", nil)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	assert.Empty(t, c.refreshing, "no refresh runs once closed")
+}
+
+func TestControllerRatingStatus(t *testing.T) {
+	testCases := []struct {
+		desc       string
+		ratingErr  error
+		wantRating *float64
+		wantStatus model.ComponentStatus
+	}{
+		{
+			desc:       "ok",
+			wantRating: ptr(4.0),
+			wantStatus: model.StatusOK,
+		},
+		{
+			desc:       "no ratings yet",
+			ratingErr:  gateway.ErrNotFound,
+			wantStatus: model.StatusNotFound,
+		},
+		{
+			desc:       "rating service down",
+			ratingErr:  errors.New("unavailable"),
+			wantStatus: model.StatusUnavailable,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			g := &flakyGateway{}
+			g.set("Title", 4, nil, tc.ratingErr)
+			c := New(g, g, DefaultMaxStaleAge)
+			t.Cleanup(c.Close)
+			c.refreshBackoff = time.Hour
+
+			details, err := c.Get(context.Background(), "1")
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantRating, details.Rating)
+			assert.Equal(t, model.Status{Metadata: model.StatusOK, Rating: tc.wantStatus}, details.Status)
+		})
+	}
+}
+
+func TestCopyDetails(t *testing.T) {
+	deletedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	details := &model.MovieDetails{
+		Rating: ptr(4.0),
+		Metadata: metadatamodel.Metadata{
+			ID:           "1",
+			Translations: map[string]metadatamodel.Translation{"fr": {Title: "Titre"}},
+			DeletedAt:    &deletedAt,
+		},
+		Degraded: []string{model.ComponentRating},
+	}
+	res := copyDetails(details)
+	require.Equal(t, details, res)
+
+	*res.Rating = 5
+	res.Degraded[0] = model.ComponentMetadata
+	res.Metadata.Translations["fr"] = metadatamodel.Translation{Title: "Autre"}
+	*res.Metadata.DeletedAt = deletedAt.Add(time.Hour)
+	assert.Equal(t, 4.0, *details.Rating)
+	assert.Equal(t, []string{model.ComponentRating}, details.Degraded)
+	assert.Equal(t, "Titre", details.Metadata.Translations["fr"].Title)
+	assert.Equal(t, deletedAt, *details.Metadata.DeletedAt)
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,111 @@
+package movie
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Aditya-Chowdhary/micro-movies/movie/internal/gateway"
+	"github.com/Aditya-Chowdhary/micro-movies/movie/pkg/model"
+)
+
+// DefaultMaxStaleAge is how long last known good details are served by default.
+const DefaultMaxStaleAge = 24 * time.Hour
+
+const (
+	// maxLastGood bounds how many movies keep last known good details.
+	maxLastGood = 10000
+
+	defaultRefreshBackoff = time.Second
+	maxRefreshAttempts    = 6
+	refreshTimeout        = 10 * time.Second
+)
+
+// lastGoodDetails are the most recent movie details fetched without any degradation.
+type lastGoodDetails struct {
+	details   model.MovieDetails
+	fetchedAt time.Time
+}
+
+func (c *Controller) getLastGood(key string) *lastGoodDetails {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	last, ok := c.lastGood[key]
+	if !ok || c.now().Sub(last.fetchedAt) > c.maxStaleAge {
+		return nil
+	}
+	return last
+}
+
+func (c *Controller) setLastGood(key string, details *model.MovieDetails) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.lastGood[key]; !ok && len(c.lastGood) >= maxLastGood {
+		// Evict an arbitrary entry; the map only backs up failed fetches.
+		for k := range c.lastGood {
+			delete(c.lastGood, k)
+			break
+		}
+	}
+	c.lastGood[key] = &lastGoodDetails{details: *details, fetchedAt: c.now()}
+}
+
+func (c *Controller) forgetLastGood(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.lastGood, key)
+}
+
+// revalidate refreshes the last known good details of a movie in the
+// background, retrying with exponential backoff until downstream recovers.
+// At most one refresh runs per movie and locales, and none once the controller
+// is closed.
+func (c *Controller) revalidate(key string, id string, locales []string) {
+	c.mu.Lock()
+	if c.refreshing[key] || c.closed.Err() != nil {
+		c.mu.Unlock()
+		return
+	}
+	c.refreshing[key] = true
+	c.refreshes.Add(1)
+	c.mu.Unlock()
+
+	go func() {
+		defer c.refreshes.Done()
+		defer func() {
+			c.mu.Lock()
+			delete(c.refreshing, key)
+			c.mu.Unlock()
+		}()
+		backoff := c.refreshBackoff
+		for i := 0; i < maxRefreshAttempts; i++ {
+			select {
+			case <-time.After(backoff):
+			case <-c.closed.Done():
+				return
+			}
+			backoff *= 2
+
+			ctx, cancel := context.WithTimeout(c.closed, refreshTimeout)
+			res := c.load(ctx, id, locales)
+			cancel()
+			if errors.Is(res.metadataErr, gateway.ErrNotFound) {
+				c.forgetLastGood(key)
+				return
+			}
+			if res.metadataErr != nil || (res.ratingErr != nil && !errors.Is(res.ratingErr, gateway.ErrNotFound)) {
+				continue
+			}
+			details := &model.MovieDetails{
+				Metadata: *res.metadata,
+				Rating:   res.rating,
+				Status:   model.Status{Metadata: model.StatusOK, Rating: model.StatusOK},
+			}
+			if res.rating == nil {
+				details.Status.Rating = model.StatusNotFound
+			}
+			c.setLastGood(key, details)
+			return
+		}
+	}()
+}
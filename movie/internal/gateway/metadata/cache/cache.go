@@ -0,0 +1,209 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"maps"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Aditya-Chowdhary/micro-movies/metadata/pkg/model"
+	"github.com/Aditya-Chowdhary/micro-movies/movie/internal/gateway"
+
+	"github.com/uber-go/tally"
+)
+
+type metadataGateway interface {
+	Get(ctx context.Context, id string, locales ...string) (*model.Metadata, error)
+}
+
+// Options configures a metadata cache.
+type Options struct {
+	// Size is the maximum number of cached entries. The least recently used
+	// entry is evicted when it is exceeded.
+	Size int
+	// TTL is how long metadata is served from the cache.
+	TTL time.Duration
+	// NegativeTTL is how long a not-found result is served from the cache.
+	// Zero disables negative caching.
+	NegativeTTL time.Duration
+}
+
+// Gateway is a read-through cache in front of a movie metadata gateway.
+// Metadata is cached per movie id and requested locales.
+type Gateway struct {
+	next metadataGateway
+	opts Options
+	now  func() time.Time
+
+	mu      sync.Mutex
+	lru     *list.List
+	entries map[key]*list.Element
+	byID    map[string]map[key]struct{}
+
+	hits      tally.Counter
+	misses    tally.Counter
+	evictions tally.Counter
+	size      tally.Gauge
+}
+
+type key struct {
+	id      string
+	locales string
+}
+
+type entry struct {
+	key     key
+	m       *model.Metadata
+	expires time.Time
+}
+
+// New creates a new metadata cache in front of next, reporting metrics to scope.
+func New(next metadataGateway, opts Options, scope tally.Scope) *Gateway {
+	scope = scope.SubScope("metadata_cache")
+	return &Gateway{
+		next:      next,
+		opts:      opts,
+		now:       time.Now,
+		lru:       list.New(),
+		entries:   map[key]*list.Element{},
+		byID:      map[string]map[key]struct{}{},
+		hits:      scope.Counter("hits"),
+		misses:    scope.Counter("misses"),
+		evictions: scope.Counter("evictions"),
+		size:      scope.Gauge("size"),
+	}
+}
+
+// Get returns movie metadata by movie id, localized to the best match of
+// locales, from the cache or else from the underlying gateway.
+func (g *Gateway) Get(ctx context.Context, id string, locales ...string) (*model.Metadata, error) {
+	k := key{id, strings.Join(locales, ",")}
+	if m, ok := g.lookup(k); ok {
+		g.hits.Inc(1)
+		if m == nil {
+			return nil, gateway.ErrNotFound
+		}
+		return m, nil
+	}
+	g.misses.Inc(1)
+
+	m, err := g.next.Get(ctx, id, locales...)
+	switch {
+	case err == nil:
+		g.store(k, m, g.opts.TTL)
+	case errors.Is(err, gateway.ErrNotFound) && g.opts.NegativeTTL > 0:
+		g.store(k, nil, g.opts.NegativeTTL)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return copyMetadata(m), nil
+}
+
+// Invalidate drops all cached entries of a movie, whatever their locales.
+func (g *Gateway) Invalidate(id string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for k := range g.byID[id] {
+		g.remove(g.entries[k])
+	}
+	g.size.Update(float64(g.lru.Len()))
+}
+
+// Clear drops all cached entries.
+func (g *Gateway) Clear() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.lru.Init()
+	g.entries = map[key]*list.Element{}
+	g.byID = map[string]map[key]struct{}{}
+	g.size.Update(0)
+}
+
+// InvalidateHandler returns an HTTP handler for administrators to drop stale
+// metadata after it is changed. A POST with an id parameter invalidates that
+// movie; one without clears the whole cache.
+func (g *Gateway) InvalidateHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		if id := r.FormValue("id"); id != "" {
+			g.Invalidate(id)
+		} else {
+			g.Clear()
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+func (g *Gateway) lookup(k key) (*model.Metadata, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	el, ok := g.entries[k]
+	if !ok {
+		return nil, false
+	}
+	e := el.Value.(*entry)
+	if !g.now().Before(e.expires) {
+		g.remove(el)
+		g.size.Update(float64(g.lru.Len()))
+		return nil, false
+	}
+	g.lru.MoveToFront(el)
+	return copyMetadata(e.m), true
+}
+
+func (g *Gateway) store(k key, m *model.Metadata, ttl time.Duration) {
+	if g.opts.Size <= 0 || ttl <= 0 {
+		return
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	e := &entry{key: k, m: copyMetadata(m), expires: g.now().Add(ttl)}
+	if el, ok := g.entries[k]; ok {
+		el.Value = e
+		g.lru.MoveToFront(el)
+		return
+	}
+	g.entries[k] = g.lru.PushFront(e)
+	if g.byID[k.id] == nil {
+		g.byID[k.id] = map[key]struct{}{}
+	}
+	g.byID[k.id][k] = struct{}{}
+	for g.lru.Len() > g.opts.Size {
+		g.remove(g.lru.Back())
+		g.evictions.Inc(1)
+	}
+	g.size.Update(float64(g.lru.Len()))
+}
+
+// remove drops a cached entry. g.mu must be held.
+func (g *Gateway) remove(el *list.Element) {
+	e := g.lru.Remove(el).(*entry)
+	delete(g.entries, e.key)
+	delete(g.byID[e.key.id], e.key)
+	if len(g.byID[e.key.id]) == 0 {
+		delete(g.byID, e.key.id)
+	}
+}
+
+// copyMetadata returns a deep copy so callers cannot modify cached metadata.
+func copyMetadata(m *model.Metadata) *model.Metadata {
+	if m == nil {
+		return nil
+	}
+	c := *m
+	if m.DeletedAt != nil {
+		deletedAt := *m.DeletedAt
+		c.DeletedAt = &deletedAt
+	}
+	c.Translations = maps.Clone(m.Translations)
+	return &c
+}
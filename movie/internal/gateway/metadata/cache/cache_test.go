@@ -0,0 +1,155 @@
+package cache
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Aditya-Chowdhary/micro-movies/metadata/pkg/model"
+	"github.com/Aditya-Chowdhary/micro-movies/movie/internal/gateway"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/uber-go/tally"
+)
+
+type fakeGateway struct {
+	calls int
+	data  map[string]*model.Metadata
+}
+
+func (f *fakeGateway) Get(_ context.Context, id string, locales ...string) (*model.Metadata, error) {
+	f.calls++
+	m, ok := f.data[id]
+	if !ok {
+		return nil, gateway.ErrNotFound
+	}
+	res := *m
+	if len(locales) > 0 {
+		res.Language = locales[0]
+	}
+	return &res, nil
+}
+
+func newTestGateway(opts Options) (*Gateway, *fakeGateway, *time.Time) {
+	next := &fakeGateway{data: map[string]*model.Metadata{
+		"1": {ID: "1", Title: "One"},
+		"2": {ID: "2", Title: "Two"},
+	}}
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	g := New(next, opts, tally.NoopScope)
+	g.now = func() time.Time { return now }
+	return g, next, &now
+}
+
+func TestGatewayTTL(t *testing.T) {
+	ctx := context.Background()
+	g, next, now := newTestGateway(Options{Size: 10, TTL: time.Minute, NegativeTTL: time.Second})
+
+	for i := 0; i < 2; i++ {
+		m, err := g.Get(ctx, "1")
+		assert.NoError(t, err)
+		assert.Equal(t, "One", m.Title)
+	}
+	assert.Equal(t, 1, next.calls)
+
+	m, err := g.Get(ctx, "1", "fr")
+	assert.NoError(t, err)
+	assert.Equal(t, "fr", m.Language, "locales are part of the cache key")
+	assert.Equal(t, 2, next.calls)
+
+	for i := 0; i < 2; i++ {
+		_, err = g.Get(ctx, "missing")
+		assert.ErrorIs(t, err, gateway.ErrNotFound)
+	}
+	assert.Equal(t, 3, next.calls, "not found is cached")
+
+	*now = now.Add(2 * time.Second)
+	_, err = g.Get(ctx, "missing")
+	assert.ErrorIs(t, err, gateway.ErrNotFound)
+	assert.Equal(t, 4, next.calls, "negative entries expire after NegativeTTL")
+
+	*now = now.Add(time.Minute)
+	_, err = g.Get(ctx, "1")
+	assert.NoError(t, err)
+	assert.Equal(t, 5, next.calls, "entries expire after TTL")
+}
+
+func TestGatewayEvictionAndInvalidation(t *testing.T) {
+	ctx := context.Background()
+	g, next, _ := newTestGateway(Options{Size: 2, TTL: time.Minute})
+
+	g.Get(ctx, "1")
+	g.Get(ctx, "1", "fr")
+	g.Get(ctx, "1")
+	g.Get(ctx, "2")
+	assert.Equal(t, 3, next.calls)
+
+	g.Get(ctx, "1")
+	assert.Equal(t, 3, next.calls, "recently used entry is kept")
+	g.Get(ctx, "1", "fr")
+	assert.Equal(t, 4, next.calls, "least recently used entry is evicted")
+
+	g.Invalidate("1")
+	g.Get(ctx, "1")
+	g.Get(ctx, "1", "fr")
+	assert.Equal(t, 6, next.calls, "invalidation drops the movie in every locale")
+
+	g.Clear()
+	g.Get(ctx, "1")
+	assert.Equal(t, 7, next.calls)
+}
+
+func TestGatewayReturnsCopies(t *testing.T) {
+	ctx := context.Background()
+	g, next, _ := newTestGateway(Options{Size: 10, TTL: time.Minute})
+	deletedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	next.data["1"].Translations = map[string]model.Translation{"fr": {Title: "Un"}}
+	next.data["1"].DeletedAt = &deletedAt
+
+	m, err := g.Get(ctx, "1")
+	assert.NoError(t, err)
+	m.Translations["fr"] = model.Translation{Title: "Changed"}
+	m.Translations["de"] = model.Translation{Title: "Eins"}
+	*m.DeletedAt = m.DeletedAt.Add(time.Hour)
+
+	m, err = g.Get(ctx, "1")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, next.calls)
+	assert.Equal(t, map[string]model.Translation{"fr": {Title: "Un"}}, m.Translations)
+	assert.Equal(t, deletedAt, *m.DeletedAt)
+}
+
+func TestInvalidateHandler(t *testing.T) {
+	ctx := context.Background()
+	g, next, _ := newTestGateway(Options{Size: 10, TTL: time.Minute})
+	h := g.InvalidateHandler()
+
+	tests := []struct {
+		desc      string
+		method    string
+		target    string
+		wantCode  int
+		wantCalls int
+	}{
+		{desc: "get is rejected", method: http.MethodGet, target: "/cache/invalidate?id=1", wantCode: http.StatusMethodNotAllowed, wantCalls: 0},
+		{desc: "invalidate one movie", method: http.MethodPost, target: "/cache/invalidate?id=1", wantCode: http.StatusNoContent, wantCalls: 1},
+		{desc: "clear", method: http.MethodPost, target: "/cache/invalidate", wantCode: http.StatusNoContent, wantCalls: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			g.Get(ctx, "1")
+			g.Get(ctx, "2")
+			next.calls = 0
+
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest(tt.method, tt.target, nil))
+			assert.Equal(t, tt.wantCode, w.Code)
+
+			g.Get(ctx, "1")
+			g.Get(ctx, "2")
+			assert.Equal(t, tt.wantCalls, next.calls)
+		})
+	}
+}
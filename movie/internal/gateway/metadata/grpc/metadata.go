@@ -4,9 +4,9 @@ import (
 	"context"
 
 	"github.com/Aditya-Chowdhary/micro-movies/gen"
-	"github.com/Aditya-Chowdhary/micro-movies/internal/grpcutil"
 	"github.com/Aditya-Chowdhary/micro-movies/metadata/pkg/model"
-	"github.com/Aditya-Chowdhary/micro-movies/pkg/discovery"
+	"github.com/Aditya-Chowdhary/micro-movies/movie/internal/gateway"
+	"github.com/Aditya-Chowdhary/micro-movies/movie/internal/gateway/routing"
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -14,37 +14,38 @@ import (
 
 // Gateway defines a movie metadata gRPC gateway
 type Gateway struct {
-	registry discovery.Registry
+	router *routing.Router
 }
 
-// New creates a new gRPC gateway for a movie metadata service.
-func New(registry discovery.Registry) *Gateway {
-	return &Gateway{registry}
+// New creates a new gRPC gateway for a movie metadata service using connections picked by router.
+func New(router *routing.Router) *Gateway {
+	return &Gateway{router}
 }
 
-// Get returns movie metadata by movie id.
-func (g *Gateway) Get(ctx context.Context, id string) (*model.Metadata, error) {
-	conn, err := grpcutil.ServiceConnection(ctx, "metadata", g.registry)
+// Get returns movie metadata by movie id, localized to the best match of locales.
+func (g *Gateway) Get(ctx context.Context, id string, locales ...string) (*model.Metadata, error) {
+	conn, done, err := g.router.Conn(ctx, "metadata")
 	if err != nil {
 		return nil, err
 	}
-	defer conn.Close()
 
 	client := gen.NewMetadataServiceClient(conn)
 	var resp *gen.GetMetadataResponse
 	const maxRetries = 5
 
 	for i := 0; i < maxRetries; i++ {
-		resp, err = client.GetMetadata(ctx, &gen.GetMetadataRequest{MovieId: id})
-		if err != nil {
-			if shouldRetry(err) {
-				continue
-			}
-			return nil, err
+		resp, err = client.GetMetadata(ctx, &gen.GetMetadataRequest{MovieId: id, Locales: locales})
+		if err == nil || !shouldRetry(err) {
+			break
 		}
-		return model.MetadataFromProto(resp.Metadata), nil
 	}
-	return nil, err
+	done(err)
+	if err != nil && status.Code(err) == codes.NotFound {
+		return nil, gateway.ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	return model.MetadataFromProto(resp.Metadata), nil
 }
 
 func shouldRetry(err error) bool {
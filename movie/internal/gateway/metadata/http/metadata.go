@@ -5,32 +5,60 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
-	"math/rand"
 	"net/http"
+	"strings"
 
 	"github.com/Aditya-Chowdhary/micro-movies/metadata/pkg/model"
 	"github.com/Aditya-Chowdhary/micro-movies/movie/internal/gateway"
+	"github.com/Aditya-Chowdhary/micro-movies/movie/internal/gateway/picker"
 	"github.com/Aditya-Chowdhary/micro-movies/pkg/discovery"
 )
 
 // Gateway defines a movie metadata HTTP gateway
 type Gateway struct {
 	registry discovery.Registry
+	picker   *picker.Picker
+
+	zone             string
+	minZoneInstances int
 }
 
 // New created a new HTTP gateway for a movie metadata service
-func New(registry discovery.Registry) *Gateway {
-	return &Gateway{registry}
+func New(registry discovery.Registry, picker *picker.Picker) *Gateway {
+	return &Gateway{registry: registry, picker: picker}
+}
+
+// PreferZone keeps calls within zone while it has at least minInstances
+// healthy instances, like the gRPC gateways. An empty zone prefers no zone.
+// It must be called before the first call.
+func (g *Gateway) PreferZone(zone string, minInstances int) {
+	g.zone = zone
+	g.minZoneInstances = minInstances
+}
+
+// addresses returns the addresses of the metadata instances to pick from.
+func (g *Gateway) addresses(ctx context.Context) ([]string, error) {
+	instances, err := g.registry.ServiceInstances(ctx, "metadata", nil)
+	if err != nil {
+		return nil, err
+	}
+	return discovery.Addresses(discovery.PreferZone(instances, g.zone, g.minZoneInstances)), nil
 }
 
-// Get gets a movie metadata by a movie id.
-func (g *Gateway) Get(ctx context.Context, id string) (*model.Metadata, error) {
-	addrs, err := g.registry.ServiceAddresses(ctx, "metadata")
+// Get gets a movie metadata by a movie id, localized to the best match of locales.
+func (g *Gateway) Get(ctx context.Context, id string, locales ...string) (*model.Metadata, error) {
+	addrs, err := g.addresses(ctx)
 	if err != nil {
 		return nil, err
 	}
+	addr, done, err := g.picker.Pick(addrs)
+	if err != nil {
+		return nil, discovery.ErrNotFound
+	}
+	var failure error
+	defer func() { done(failure) }()
 
-	url := "http://" + addrs[rand.Intn(len(addrs))] + "/metadata"
+	url := "http://" + addr + "/metadata"
 	log.Printf("Calling metadata service. Request: GET " + url)
 	req, err := http.NewRequest(http.MethodGet, url, nil)
 	if err != nil {
@@ -41,12 +69,19 @@ func (g *Gateway) Get(ctx context.Context, id string) (*model.Metadata, error) {
 	values := req.URL.Query()
 	values.Add("id", id)
 	req.URL.RawQuery = values.Encode()
+	if len(locales) > 0 {
+		req.Header.Set("Accept-Language", strings.Join(locales, ", "))
+	}
 
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
+		failure = err
 		return nil, err
 	}
 	defer resp.Body.Close()
+	if resp.StatusCode/100 == 5 {
+		failure = fmt.Errorf("server error response: %v", resp.Status)
+	}
 
 	if resp.StatusCode == http.StatusNotFound {
 		return nil, gateway.ErrNotFound
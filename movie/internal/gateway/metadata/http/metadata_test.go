@@ -0,0 +1,62 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Aditya-Chowdhary/micro-movies/metadata/pkg/model"
+	"github.com/Aditya-Chowdhary/micro-movies/movie/internal/gateway/picker"
+	"github.com/Aditya-Chowdhary/micro-movies/pkg/discovery"
+	"github.com/Aditya-Chowdhary/micro-movies/pkg/discovery/memory"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGatewayPreferZone(t *testing.T) {
+	ctx := context.Background()
+	registry := memory.NewRegistry()
+	defer registry.Close()
+	for _, zone := range []string{"zone-a", "zone-b"} {
+		zone := zone
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			json.NewEncoder(w).Encode(&model.Metadata{ID: r.URL.Query().Get("id"), Title: zone})
+		}))
+		defer srv.Close()
+		addr := strings.TrimPrefix(srv.URL, "http://")
+		require.NoError(t, registry.Register(ctx, zone, "metadata", addr, map[string]string{discovery.MetadataZone: zone}))
+	}
+
+	testCases := []struct {
+		desc         string
+		zone         string
+		minInstances int
+		want         map[string]bool
+	}{
+		{desc: "no zone", want: map[string]bool{"zone-a": true, "zone-b": true}},
+		{desc: "local zone", zone: "zone-b", want: map[string]bool{"zone-b": true}},
+		{desc: "spill over", zone: "zone-b", minInstances: 2, want: map[string]bool{"zone-a": true, "zone-b": true}},
+		{desc: "unknown zone", zone: "zone-c", want: map[string]bool{"zone-a": true, "zone-b": true}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			p, err := picker.New(picker.RoundRobin)
+			require.NoError(t, err)
+			g := New(registry, p)
+			g.PreferZone(tc.zone, tc.minInstances)
+
+			// Round robin calls every candidate instance once.
+			got := map[string]bool{}
+			for i := 0; i < 2; i++ {
+				m, err := g.Get(ctx, "1")
+				require.NoError(t, err)
+				got[m.Title] = true
+			}
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package picker
+
+import (
+	"fmt"
+
+	"google.golang.org/grpc/balancer"
+	"google.golang.org/grpc/balancer/base"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func init() {
+	for _, policy := range Policies {
+		balancer.Register(&balancerBuilder{policy})
+	}
+}
+
+// BalancerName returns the name of the gRPC balancer picking with policy.
+func BalancerName(policy Policy) string {
+	return "picker_" + string(policy)
+}
+
+// ServiceConfig returns a gRPC service config balancing calls with policy.
+func ServiceConfig(policy Policy) string {
+	return fmt.Sprintf(`{"loadBalancingConfig": [{%q: {}}]}`, BalancerName(policy))
+}
+
+// balancerBuilder builds gRPC balancers that keep a picker per client connection.
+type balancerBuilder struct {
+	policy Policy
+}
+
+func (b *balancerBuilder) Name() string {
+	return BalancerName(b.policy)
+}
+
+func (b *balancerBuilder) Build(cc balancer.ClientConn, opts balancer.BuildOptions) balancer.Balancer {
+	p, err := New(b.policy)
+	if err != nil {
+		panic(err)
+	}
+	return base.NewBalancerBuilder(b.Name(), &pickerBuilder{p}, base.Config{}).Build(cc, opts)
+}
+
+type pickerBuilder struct {
+	picker *Picker
+}
+
+func (pb *pickerBuilder) Build(info base.PickerBuildInfo) balancer.Picker {
+	if len(info.ReadySCs) == 0 {
+		return base.NewErrPicker(balancer.ErrNoSubConnAvailable)
+	}
+	gp := &grpcPicker{picker: pb.picker, subConns: make(map[string]balancer.SubConn, len(info.ReadySCs))}
+	for sc, sci := range info.ReadySCs {
+		gp.subConns[sci.Address.Addr] = sc
+		gp.addrs = append(gp.addrs, sci.Address.Addr)
+	}
+	return gp
+}
+
+// grpcPicker picks among the ready connections of a gRPC client connection.
+type grpcPicker struct {
+	picker   *Picker
+	addrs    []string
+	subConns map[string]balancer.SubConn
+}
+
+func (gp *grpcPicker) Pick(balancer.PickInfo) (balancer.PickResult, error) {
+	addr, done, err := gp.picker.Pick(gp.addrs)
+	if err != nil {
+		return balancer.PickResult{}, balancer.ErrNoSubConnAvailable
+	}
+	return balancer.PickResult{
+		SubConn: gp.subConns[addr],
+		Done: func(info balancer.DoneInfo) {
+			done(instanceError(info.Err))
+		},
+	}, nil
+}
+
+// instanceError returns err if it means the instance failed to serve the
+// call, rather than the call itself being rejected.
+func instanceError(err error) error {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Internal, codes.Unknown:
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,225 @@
+// Package picker implements client-side load-balancing policies for choosing
+// the service instance a gateway calls.
+package picker
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Policy is a load-balancing policy.
+type Policy string
+
+const (
+	// Random picks a random instance.
+	Random Policy = "random"
+	// RoundRobin cycles through instances in address order.
+	RoundRobin Policy = "round_robin"
+	// LeastRequest picks the instance with the fewest outstanding calls.
+	LeastRequest Policy = "least_request"
+	// PowerOfTwoEWMA picks two random instances and calls the one with the
+	// lower latency, estimated by an exponentially weighted moving average
+	// and scaled by its outstanding calls.
+	PowerOfTwoEWMA Policy = "p2c_ewma"
+)
+
+// Policies lists all supported policies.
+var Policies = []Policy{Random, RoundRobin, LeastRequest, PowerOfTwoEWMA}
+
+// ErrNoAddresses is returned when there is no address to pick from.
+var ErrNoAddresses = errors.New("no addresses to pick from")
+
+const (
+	// decay is the time constant of the latency moving average.
+	decay = 10 * time.Second
+	// failurePenalty is the latency recorded for a failed call, so instances
+	// that fail fast are not preferred.
+	failurePenalty = time.Second
+	// unmeasuredLatency is the latency assumed for an instance with
+	// outstanding calls but no estimate, when no instance has an estimate.
+	unmeasuredLatency = 100 * time.Millisecond
+)
+
+// ParsePolicy returns the policy named s. An empty name selects RoundRobin.
+func ParsePolicy(s string) (Policy, error) {
+	if s == "" {
+		return RoundRobin, nil
+	}
+	if p := Policy(s); slices.Contains(Policies, p) {
+		return p, nil
+	}
+	return "", fmt.Errorf("unknown load-balancing policy %q", s)
+}
+
+// Picker selects the instance address for each call.
+type Picker struct {
+	policy Policy
+	now    func() time.Time
+
+	mu    sync.Mutex
+	rand  *rand.Rand
+	next  int
+	stats map[string]*stats
+}
+
+// stats tracks the load of an instance.
+type stats struct {
+	outstanding int
+	// latency is the moving average of call latency. Zero means no call has completed yet.
+	latency time.Duration
+	updated time.Time
+}
+
+// New creates a new picker using policy.
+func New(policy Policy) (*Picker, error) {
+	if !slices.Contains(Policies, policy) {
+		return nil, fmt.Errorf("unknown load-balancing policy %q", policy)
+	}
+	return &Picker{
+		policy: policy,
+		now:    time.Now,
+		rand:   rand.New(rand.NewSource(time.Now().UnixNano())),
+		stats:  map[string]*stats{},
+	}, nil
+}
+
+// Pick selects one of addrs. The returned done function must be called with
+// the outcome once the call to the selected address finishes; err should only
+// be non-nil when the instance failed to serve the call.
+func (p *Picker) Pick(addrs []string) (addr string, done func(err error), err error) {
+	if len(addrs) == 0 {
+		return "", nil, ErrNoAddresses
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.pruneLocked(addrs)
+
+	switch p.policy {
+	case RoundRobin:
+		sorted := slices.Clone(addrs)
+		slices.Sort(sorted)
+		addr = sorted[p.next%len(sorted)]
+		p.next++
+	case LeastRequest:
+		addr = p.leastRequestLocked(addrs)
+	case PowerOfTwoEWMA:
+		addr = p.powerOfTwoLocked(addrs)
+	default:
+		addr = addrs[p.rand.Intn(len(addrs))]
+	}
+
+	s := p.statsLocked(addr)
+	s.outstanding++
+	start := p.now()
+	var once sync.Once
+	return addr, func(err error) {
+		once.Do(func() { p.done(addr, start, err) })
+	}, nil
+}
+
+func (p *Picker) leastRequestLocked(addrs []string) string {
+	// Start at a random offset so ties are spread across instances.
+	offset := p.rand.Intn(len(addrs))
+	best := addrs[offset]
+	for i := 1; i < len(addrs); i++ {
+		addr := addrs[(offset+i)%len(addrs)]
+		if p.statsLocked(addr).outstanding < p.statsLocked(best).outstanding {
+			best = addr
+		}
+	}
+	return best
+}
+
+func (p *Picker) powerOfTwoLocked(addrs []string) string {
+	if len(addrs) == 1 {
+		return addrs[0]
+	}
+	i := p.rand.Intn(len(addrs))
+	j := p.rand.Intn(len(addrs) - 1)
+	if j >= i {
+		j++
+	}
+	a, b := addrs[i], addrs[j]
+	if p.costLocked(b) < p.costLocked(a) {
+		return b
+	}
+	return a
+}
+
+// costLocked estimates how long a new call to addr would take. Idle instances
+// without a latency estimate cost nothing so they are tried. Once they have
+// outstanding calls they are assumed as fast as the average instance, so an
+// instance whose calls never finish is not picked again and again.
+func (p *Picker) costLocked(addr string) float64 {
+	s := p.statsLocked(addr)
+	latency := s.latency
+	if latency == 0 && s.outstanding > 0 {
+		latency = p.meanLatencyLocked()
+	}
+	return float64(latency) * float64(s.outstanding+1)
+}
+
+// meanLatencyLocked returns the mean latency estimate of the instances that
+// have one, or unmeasuredLatency if none has.
+func (p *Picker) meanLatencyLocked() time.Duration {
+	var sum time.Duration
+	n := 0
+	for _, s := range p.stats {
+		if s.latency > 0 {
+			sum += s.latency
+			n++
+		}
+	}
+	if n == 0 {
+		return unmeasuredLatency
+	}
+	return sum / time.Duration(n)
+}
+
+func (p *Picker) done(addr string, start time.Time, err error) {
+	now := p.now()
+	latency := now.Sub(start)
+	if err != nil && latency < failurePenalty {
+		latency = failurePenalty
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	s := p.statsLocked(addr)
+	if s.outstanding > 0 {
+		s.outstanding--
+	}
+	if s.latency == 0 {
+		s.latency = latency
+	} else {
+		w := math.Exp(-float64(now.Sub(s.updated)) / float64(decay))
+		s.latency = time.Duration(float64(s.latency)*w + float64(latency)*(1-w))
+	}
+	s.updated = now
+}
+
+func (p *Picker) statsLocked(addr string) *stats {
+	s, ok := p.stats[addr]
+	if !ok {
+		s = &stats{}
+		p.stats[addr] = s
+	}
+	return s
+}
+
+// pruneLocked drops idle stats of instances missing from addrs.
+func (p *Picker) pruneLocked(addrs []string) {
+	if len(p.stats) <= len(addrs) {
+		return
+	}
+	for addr, s := range p.stats {
+		if s.outstanding == 0 && !slices.Contains(addrs, addr) {
+			delete(p.stats, addr)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package picker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Aditya-Chowdhary/micro-movies/internal/grpcutil"
+	"github.com/Aditya-Chowdhary/micro-movies/internal/grpcutil/grpctest"
+	"github.com/Aditya-Chowdhary/micro-movies/pkg/discovery/memory"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestParsePolicy(t *testing.T) {
+	p, err := ParsePolicy("")
+	require.NoError(t, err)
+	assert.Equal(t, RoundRobin, p)
+	p, err = ParsePolicy("p2c_ewma")
+	require.NoError(t, err)
+	assert.Equal(t, PowerOfTwoEWMA, p)
+	_, err = ParsePolicy("fastest")
+	assert.Error(t, err)
+}
+
+func TestPickNoAddresses(t *testing.T) {
+	for _, policy := range Policies {
+		p, err := New(policy)
+		require.NoError(t, err)
+		_, _, err = p.Pick(nil)
+		assert.ErrorIs(t, err, ErrNoAddresses, policy)
+	}
+}
+
+func TestRoundRobin(t *testing.T) {
+	p, err := New(RoundRobin)
+	require.NoError(t, err)
+	var got []string
+	for i := 0; i < 4; i++ {
+		// Registries do not return addresses in a stable order.
+		addrs := []string{"b", "a", "c"}
+		if i%2 == 1 {
+			addrs = []string{"c", "b", "a"}
+		}
+		addr, done, err := p.Pick(addrs)
+		require.NoError(t, err)
+		done(nil)
+		got = append(got, addr)
+	}
+	assert.Equal(t, []string{"a", "b", "c", "a"}, got)
+}
+
+func TestLeastRequest(t *testing.T) {
+	p, err := New(LeastRequest)
+	require.NoError(t, err)
+	addrs := []string{"a", "b", "c"}
+
+	picked := map[string]func(error){}
+	for i := 0; i < 3; i++ {
+		addr, done, err := p.Pick(addrs)
+		require.NoError(t, err)
+		picked[addr] = done
+	}
+	assert.Len(t, picked, 3, "each instance gets one outstanding call")
+
+	picked["b"](nil)
+	picked["b"](nil) // done is idempotent
+	addr, _, err := p.Pick(addrs)
+	require.NoError(t, err)
+	assert.Equal(t, "b", addr)
+}
+
+func TestPowerOfTwoEWMA(t *testing.T) {
+	p, err := New(PowerOfTwoEWMA)
+	require.NoError(t, err)
+	now := time.Now()
+	p.now = func() time.Time { return now }
+	addrs := []string{"fast", "slow"}
+
+	call := func(addr string, latency time.Duration, callErr error) {
+		for {
+			got, done, err := p.Pick(addrs)
+			require.NoError(t, err)
+			if got != addr {
+				done(nil)
+				continue
+			}
+			now = now.Add(latency)
+			done(callErr)
+			return
+		}
+	}
+	call("fast", 10*time.Millisecond, nil)
+	call("slow", 500*time.Millisecond, nil)
+
+	for i := 0; i < 10; i++ {
+		addr, done, err := p.Pick(addrs)
+		require.NoError(t, err)
+		assert.Equal(t, "fast", addr)
+		now = now.Add(10 * time.Millisecond)
+		done(nil)
+	}
+
+	// Failures are penalised even when they are quick.
+	now = now.Add(time.Minute)
+	call("fast", time.Millisecond, errors.New("unavailable"))
+	addr, _, err := p.Pick(addrs)
+	require.NoError(t, err)
+	assert.Equal(t, "slow", addr)
+}
+
+func TestPowerOfTwoEWMAHungInstance(t *testing.T) {
+	p, err := New(PowerOfTwoEWMA)
+	require.NoError(t, err)
+	now := time.Now()
+	p.now = func() time.Time { return now }
+	addrs := []string{"hung", "ok"}
+
+	calls := map[string]int{}
+	for i := 0; i < 20; i++ {
+		addr, done, err := p.Pick(addrs)
+		require.NoError(t, err)
+		calls[addr]++
+		if addr == "ok" {
+			now = now.Add(10 * time.Millisecond)
+			done(nil)
+		}
+	}
+	assert.Equal(t, 1, calls["hung"], "an instance whose calls never finish is not picked again")
+}
+
+func TestPruneStats(t *testing.T) {
+	p, err := New(LeastRequest)
+	require.NoError(t, err)
+	addrs := []string{"a", "b", "c"}
+	for range addrs {
+		_, done, err := p.Pick(addrs)
+		require.NoError(t, err)
+		done(nil)
+	}
+	assert.Len(t, p.stats, 3)
+	_, _, err = p.Pick([]string{"a"})
+	require.NoError(t, err)
+	assert.Len(t, p.stats, 1, "stats of removed instances are dropped")
+}
+
+func TestGRPCBalancer(t *testing.T) {
+	ctx := context.Background()
+	a, b := grpctest.StartCountingServer(t), grpctest.StartCountingServer(t)
+	registry := memory.NewRegistry()
+	defer registry.Close()
+	require.NoError(t, registry.Register(ctx, "a", "svc", a.Addr, nil))
+	require.NoError(t, registry.Register(ctx, "b", "svc", b.Addr, nil))
+
+	conns := grpcutil.NewConnManager(registry, grpc.WithDefaultServiceConfig(ServiceConfig(RoundRobin)))
+	defer conns.Close()
+	conn, err := conns.Conn(ctx, "svc")
+	require.NoError(t, err)
+	client := healthpb.NewHealthClient(conn)
+
+	// Wait for both instances to be ready so calls are spread evenly.
+	require.Eventually(t, func() bool {
+		if _, err := client.Check(ctx, &healthpb.HealthCheckRequest{}, grpc.WaitForReady(true)); err != nil {
+			return false
+		}
+		return a.Count() > 0 && b.Count() > 0
+	}, 5*time.Second, 10*time.Millisecond)
+
+	before := a.Count()
+	for i := 0; i < 10; i++ {
+		_, err := client.Check(ctx, &healthpb.HealthCheckRequest{})
+		require.NoError(t, err)
+	}
+	assert.Equal(t, before+5, a.Count())
+}
@@ -4,32 +4,37 @@ import (
 	"context"
 
 	"github.com/Aditya-Chowdhary/micro-movies/gen"
-	"github.com/Aditya-Chowdhary/micro-movies/internal/grpcutil"
-	"github.com/Aditya-Chowdhary/micro-movies/pkg/discovery"
+	"github.com/Aditya-Chowdhary/micro-movies/movie/internal/gateway"
+	"github.com/Aditya-Chowdhary/micro-movies/movie/internal/gateway/routing"
 	"github.com/Aditya-Chowdhary/micro-movies/rating/pkg/model"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // Gateway defines a gRPC gateway for a rating service
 type Gateway struct {
-	registry discovery.Registry
+	router *routing.Router
 }
 
-// New creates a new gRPC gateway for a rating service.
-func New(registry discovery.Registry) *Gateway {
-	return &Gateway{registry}
+// New creates a new gRPC gateway for a rating service using connections picked by router.
+func New(router *routing.Router) *Gateway {
+	return &Gateway{router}
 }
 
 // GetAggregatedRating returns the aggregated rating for a record or ErrNotFound if there are no ratings for it.
 func (g *Gateway) GetAggregatedRating(ctx context.Context, recordID model.RecordID, recordType model.RecordType) (float64, error) {
-	conn, err := grpcutil.ServiceConnection(ctx, "rating", g.registry)
+	conn, done, err := g.router.Conn(ctx, "rating")
 	if err != nil {
 		return 0, err
 	}
-	defer conn.Close()
 
 	client := gen.NewRatingServiceClient(conn)
 	resp, err := client.GetAggregatedRating(ctx, &gen.GetAggregatedRatingRequest{RecordId: string(recordID), RecordType: string(recordType)})
-	if err != nil {
+	done(err)
+	if err != nil && status.Code(err) == codes.NotFound {
+		return 0, gateway.ErrNotFound
+	} else if err != nil {
 		return 0, err
 	}
 	return resp.RatingValue, nil
@@ -5,10 +5,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
-	"math/rand"
 	"net/http"
 
 	"github.com/Aditya-Chowdhary/micro-movies/movie/internal/gateway"
+	"github.com/Aditya-Chowdhary/micro-movies/movie/internal/gateway/picker"
 	"github.com/Aditya-Chowdhary/micro-movies/pkg/discovery"
 	"github.com/Aditya-Chowdhary/micro-movies/rating/pkg/model"
 )
@@ -16,21 +16,48 @@ import (
 // Gateway defines an HTTP gateway for a rating service
 type Gateway struct {
 	registry discovery.Registry
+	picker   *picker.Picker
+
+	zone             string
+	minZoneInstances int
 }
 
 // New creates a new HTTP gateway for a rating service
-func New(registry discovery.Registry) *Gateway {
-	return &Gateway{registry}
+func New(registry discovery.Registry, picker *picker.Picker) *Gateway {
+	return &Gateway{registry: registry, picker: picker}
+}
+
+// PreferZone keeps calls within zone while it has at least minInstances
+// healthy instances, like the gRPC gateways. An empty zone prefers no zone.
+// It must be called before the first call.
+func (g *Gateway) PreferZone(zone string, minInstances int) {
+	g.zone = zone
+	g.minZoneInstances = minInstances
+}
+
+// addresses returns the addresses of the rating instances to pick from.
+func (g *Gateway) addresses(ctx context.Context) ([]string, error) {
+	instances, err := g.registry.ServiceInstances(ctx, "rating", nil)
+	if err != nil {
+		return nil, err
+	}
+	return discovery.Addresses(discovery.PreferZone(instances, g.zone, g.minZoneInstances)), nil
 }
 
 // GetAggregatedRating returns the aggregated rating for a record or ErrNotFound
 func (g *Gateway) GetAggregatedRating(ctx context.Context, recordID model.RecordID, recordType model.RecordType) (float64, error) {
-	addrs, err := g.registry.ServiceAddresses(ctx, "rating")
+	addrs, err := g.addresses(ctx)
 	if err != nil {
 		return 0, err
 	}
+	addr, done, err := g.picker.Pick(addrs)
+	if err != nil {
+		return 0, discovery.ErrNotFound
+	}
+	var failure error
+	defer func() { done(failure) }()
 
-	url := "http://" + addrs[rand.Intn(len(addrs))] + "/rating"
+	url := "http://" + addr + "/rating"
 	log.Printf("Calling rating service. Request: GET " + url)
 	req, err := http.NewRequest(http.MethodGet, url, nil)
 	if err != nil {
@@ -45,9 +72,13 @@ func (g *Gateway) GetAggregatedRating(ctx context.Context, recordID model.Record
 
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
+		failure = err
 		return 0, err
 	}
 	defer resp.Body.Close()
+	if resp.StatusCode/100 == 5 {
+		failure = fmt.Errorf("server error response: %v", resp.Status)
+	}
 
 	if resp.StatusCode == http.StatusNotFound {
 		return 0, gateway.ErrNotFound
@@ -65,11 +96,17 @@ func (g *Gateway) GetAggregatedRating(ctx context.Context, recordID model.Record
 
 // PutRating writes a rating.
 func (g *Gateway) PutRating(ctx context.Context, recordID model.RecordID, recordType model.RecordType, rating *model.Rating) error {
-	addrs, err := g.registry.ServiceAddresses(ctx, "rating")
+	addrs, err := g.addresses(ctx)
 	if err != nil {
 		return err
 	}
-	url := "http://" + addrs[rand.Intn(len(addrs))] + "/ratng"
+	addr, done, err := g.picker.Pick(addrs)
+	if err != nil {
+		return discovery.ErrNotFound
+	}
+	var failure error
+	defer func() { done(failure) }()
+	url := "http://" + addr + "/ratng"
 	log.Printf("Calling rating service. Request: PUT " + url)
 	req, err := http.NewRequest(http.MethodPut, url, nil)
 	if err != nil {
@@ -85,9 +122,13 @@ func (g *Gateway) PutRating(ctx context.Context, recordID model.RecordID, record
 
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
+		failure = err
 		return err
 	}
 	defer resp.Body.Close()
+	if resp.StatusCode/100 == 5 {
+		failure = fmt.Errorf("server error response: %v", resp.Status)
+	}
 
 	if resp.StatusCode/100 != 2 {
 		return fmt.Errorf("non-2xx response: %v", resp)
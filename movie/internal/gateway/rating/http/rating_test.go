@@ -0,0 +1,64 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Aditya-Chowdhary/micro-movies/movie/internal/gateway/picker"
+	"github.com/Aditya-Chowdhary/micro-movies/pkg/discovery"
+	"github.com/Aditya-Chowdhary/micro-movies/pkg/discovery/memory"
+	"github.com/Aditya-Chowdhary/micro-movies/rating/pkg/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGatewayPreferZone(t *testing.T) {
+	ctx := context.Background()
+	registry := memory.NewRegistry()
+	defer registry.Close()
+	// Each instance answers with the number of its zone as the rating.
+	zones := map[float64]string{1: "zone-a", 2: "zone-b"}
+	for rating, zone := range zones {
+		rating := rating
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			json.NewEncoder(w).Encode(rating)
+		}))
+		defer srv.Close()
+		addr := strings.TrimPrefix(srv.URL, "http://")
+		require.NoError(t, registry.Register(ctx, zone, "rating", addr, map[string]string{discovery.MetadataZone: zone}))
+	}
+
+	testCases := []struct {
+		desc         string
+		zone         string
+		minInstances int
+		want         map[string]bool
+	}{
+		{desc: "no zone", want: map[string]bool{"zone-a": true, "zone-b": true}},
+		{desc: "local zone", zone: "zone-b", want: map[string]bool{"zone-b": true}},
+		{desc: "spill over", zone: "zone-b", minInstances: 2, want: map[string]bool{"zone-a": true, "zone-b": true}},
+		{desc: "unknown zone", zone: "zone-c", want: map[string]bool{"zone-a": true, "zone-b": true}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			p, err := picker.New(picker.RoundRobin)
+			require.NoError(t, err)
+			g := New(registry, p)
+			g.PreferZone(tc.zone, tc.minInstances)
+
+			// Round robin calls every candidate instance once.
+			got := map[string]bool{}
+			for i := 0; i < 2; i++ {
+				v, err := g.GetAggregatedRating(ctx, model.RecordID("1"), model.RecordTypeMovie)
+				require.NoError(t, err)
+				got[zones[v]] = true
+			}
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
@@ -0,0 +1,160 @@
+// Package routing splits the movie service's calls to a downstream service
+// between groups of its instances, e.g. to send a share of traffic to a
+// canary build.
+package routing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+
+	"github.com/Aditya-Chowdhary/micro-movies/internal/grpcutil"
+
+	"github.com/uber-go/tally"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// DefaultVersion is the version calls are reported under when a service has no routes.
+const DefaultVersion = "default"
+
+// Route sends a weighted share of a service's calls to the instances whose
+// metadata matches, among those allowed by the service's discovery filter.
+// Routes do not fall back to each other: while no instance matches a route,
+// its share of calls fails with codes.Unavailable.
+type Route struct {
+	// Version names the route in metrics, e.g. v2 or canary.
+	Version string `yaml:"version"`
+	// Match is the instance metadata the route selects, e.g. track: canary.
+	Match map[string]string `yaml:"match"`
+	// Weight is the route's share of calls relative to the other routes of the service.
+	Weight int `yaml:"weight"`
+}
+
+// Rules are the routes of each service, keyed by service name.
+type Rules map[string][]Route
+
+// Validate checks every service has uniquely named routes with non-negative
+// weights, at least one of them positive.
+func (r Rules) Validate() error {
+	for service, routes := range r {
+		total := 0
+		versions := map[string]bool{}
+		for _, route := range routes {
+			if route.Version == "" {
+				return fmt.Errorf("route of %s is missing a version", service)
+			}
+			if versions[route.Version] {
+				return fmt.Errorf("duplicate route %s of %s", route.Version, service)
+			}
+			versions[route.Version] = true
+			if route.Weight < 0 {
+				return fmt.Errorf("route %s of %s has a negative weight", route.Version, service)
+			}
+			total += route.Weight
+		}
+		if len(routes) > 0 && total == 0 {
+			return fmt.Errorf("routes of %s have no weight", service)
+		}
+	}
+	return nil
+}
+
+// Router picks the connection for each call to a downstream service according
+// to its routes, and reports requests and errors per service and version.
+// Services without routes are called through their default connection.
+type Router struct {
+	conns *grpcutil.ConnManager
+	scope tally.Scope
+
+	mu    sync.RWMutex
+	rules Rules
+}
+
+// New creates a new router over connections from conns, reporting metrics to scope.
+func New(conns *grpcutil.ConnManager, scope tally.Scope) *Router {
+	return &Router{
+		conns: conns,
+		scope: scope.SubScope("gateway"),
+		rules: Rules{},
+	}
+}
+
+// SetRules replaces the routes of every service. It is safe to call while
+// calls are being routed.
+func (r *Router) SetRules(rules Rules) error {
+	if err := rules.Validate(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.rules = rules
+	return nil
+}
+
+// Conn returns the connection for a call to serviceName. done must be called
+// with the outcome of the call.
+func (r *Router) Conn(ctx context.Context, serviceName string) (conn *grpc.ClientConn, done func(err error), err error) {
+	route := r.pick(serviceName)
+	if route == nil {
+		conn, err = r.conns.Conn(ctx, serviceName)
+		route = &Route{Version: DefaultVersion}
+	} else {
+		conn, err = r.conns.ConnMatching(ctx, serviceName, route.Match)
+	}
+
+	scope := r.scope.Tagged(map[string]string{"target": serviceName, "version": route.Version})
+	scope.Counter("requests").Inc(1)
+	done = func(err error) {
+		if isFailure(err) {
+			scope.Counter("errors").Inc(1)
+		}
+	}
+	if err != nil {
+		done(err)
+		return nil, nil, err
+	}
+	return conn, done, nil
+}
+
+// pick returns a weighted random route of serviceName, or nil if it has none.
+func (r *Router) pick(serviceName string) *Route {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	routes := r.rules[serviceName]
+	total := 0
+	for _, route := range routes {
+		total += route.Weight
+	}
+	if total == 0 {
+		return nil
+	}
+	n := rand.Intn(total)
+	for i := range routes {
+		if n < routes[i].Weight {
+			route := routes[i]
+			return &route
+		}
+		n -= routes[i].Weight
+	}
+	return nil
+}
+
+// isFailure reports whether err means the service failed to serve a call,
+// as opposed to a call for something that does not exist.
+func isFailure(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+	switch status.Code(err) {
+	case codes.NotFound, codes.Canceled:
+		return false
+	}
+	return true
+}
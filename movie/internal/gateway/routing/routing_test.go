@@ -0,0 +1,111 @@
+package routing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Aditya-Chowdhary/micro-movies/internal/grpcutil"
+	"github.com/Aditya-Chowdhary/micro-movies/pkg/discovery/memory"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uber-go/tally"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRulesValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		rules   Rules
+		wantErr bool
+	}{
+		{
+			name:  "empty",
+			rules: Rules{},
+		},
+		{
+			name: "canary",
+			rules: Rules{"rating": {
+				{Version: "stable", Match: map[string]string{"track": "stable"}, Weight: 95},
+				{Version: "canary", Match: map[string]string{"track": "canary"}, Weight: 5},
+			}},
+		},
+		{
+			name:    "missing version",
+			rules:   Rules{"rating": {{Weight: 1}}},
+			wantErr: true,
+		},
+		{
+			name:    "duplicate version",
+			rules:   Rules{"rating": {{Version: "v1", Weight: 1}, {Version: "v1", Weight: 1}}},
+			wantErr: true,
+		},
+		{
+			name:    "negative weight",
+			rules:   Rules{"rating": {{Version: "v1", Weight: 2}, {Version: "v2", Weight: -1}}},
+			wantErr: true,
+		},
+		{
+			name:    "no weight",
+			rules:   Rules{"rating": {{Version: "v1"}}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.rules.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestRouter(t *testing.T) {
+	ctx := context.Background()
+	registry := memory.NewRegistry()
+	defer registry.Close()
+	conns := grpcutil.NewConnManager(registry)
+	defer conns.Close()
+	scope := tally.NewTestScope("", nil)
+	r := New(conns, scope)
+
+	call := func(err error) {
+		t.Helper()
+		_, done, connErr := r.Conn(ctx, "rating")
+		require.NoError(t, connErr)
+		done(err)
+	}
+	call(nil)
+
+	require.NoError(t, r.SetRules(Rules{"rating": {
+		{Version: "stable", Match: map[string]string{"track": "stable"}, Weight: 95},
+		{Version: "canary", Match: map[string]string{"track": "canary"}, Weight: 5},
+	}}))
+	const calls = 10000
+	for i := 0; i < calls; i++ {
+		call(status.Error(codes.Unavailable, "unavailable"))
+	}
+	call(status.Error(codes.NotFound, "not found"))
+
+	assert.Error(t, r.SetRules(Rules{"rating": {{Version: "v1"}}}), "invalid rules are rejected")
+
+	counters := scope.Snapshot().Counters()
+	value := func(name, version string) int64 {
+		c, ok := counters["gateway."+name+"+target=rating,version="+version]
+		if !ok {
+			return 0
+		}
+		return c.Value()
+	}
+	assert.Equal(t, int64(1), value("requests", DefaultVersion))
+	assert.Equal(t, int64(0), value("errors", DefaultVersion))
+
+	canary := value("requests", "canary")
+	assert.Equal(t, int64(calls+1), canary+value("requests", "stable"))
+	assert.InDelta(t, calls*5/100, canary, calls/100, "canary gets about 5% of calls")
+	assert.Equal(t, int64(calls), value("errors", "canary")+value("errors", "stable"), "not found is not an error")
+}
@@ -3,13 +3,17 @@ package grpc
 import (
 	"context"
 	"errors"
+	"strings"
 
 	"github.com/Aditya-Chowdhary/micro-movies/gen"
 	"github.com/Aditya-Chowdhary/micro-movies/metadata/pkg/model"
 	"github.com/Aditya-Chowdhary/micro-movies/movie/internal/controller/movie"
+	moviemodel "github.com/Aditya-Chowdhary/micro-movies/movie/pkg/model"
 
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
 )
 
 // Handler defines a movie gRPC handler
@@ -28,7 +32,7 @@ func (h *Handler) GetMovieDetails(ctx context.Context, req *gen.GetMovieDetailsR
 	if req == nil || req.MovieId == "" {
 		return nil, status.Errorf(codes.InvalidArgument, "nil req or empty id")
 	}
-	m, err := h.ctrl.Get(ctx, req.MovieId)
+	m, err := h.ctrl.Get(ctx, req.MovieId, callerLocales(ctx)...)
 	if err != nil && errors.Is(err, movie.ErrNotFound) {
 		return nil, status.Errorf(codes.NotFound, err.Error())
 	} else if err != nil {
@@ -38,10 +42,46 @@ func (h *Handler) GetMovieDetails(ctx context.Context, req *gen.GetMovieDetailsR
 	if m.Rating != nil {
 		rating = *m.Rating
 	}
+	var age *durationpb.Duration
+	if m.Stale {
+		age = durationpb.New(m.Age)
+	}
 	return &gen.GetMovieDetailsResponse{
 		MovieDetails: &gen.MovieDetails{
 			Metadata: model.MetadataToProto(&m.Metadata),
 			Rating:   rating,
+			Stale:    m.Stale,
+			Age:      age,
+			Degraded: m.Degraded,
+
+			MetadataStatus: componentStatusToProto(m.Status.Metadata),
+			RatingStatus:   componentStatusToProto(m.Status.Rating),
 		},
 	}, nil
 }
+
+func componentStatusToProto(s moviemodel.ComponentStatus) gen.ComponentStatus {
+	switch s {
+	case moviemodel.StatusOK:
+		return gen.ComponentStatus_COMPONENT_STATUS_OK
+	case moviemodel.StatusNotFound:
+		return gen.ComponentStatus_COMPONENT_STATUS_NOT_FOUND
+	case moviemodel.StatusUnavailable:
+		return gen.ComponentStatus_COMPONENT_STATUS_UNAVAILABLE
+	}
+	return gen.ComponentStatus_COMPONENT_STATUS_UNSPECIFIED
+}
+
+// callerLocales returns the languages preferred by the caller, read from the
+// accept-language gRPC metadata key in Accept-Language header syntax.
+func callerLocales(ctx context.Context) []string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil
+	}
+	values := md.Get("accept-language")
+	if len(values) == 0 {
+		return nil
+	}
+	return model.ParseAcceptLanguage(strings.Join(values, ","))
+}
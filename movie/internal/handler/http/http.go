@@ -6,6 +6,7 @@ import (
 	"log"
 	"net/http"
 
+	"github.com/Aditya-Chowdhary/micro-movies/metadata/pkg/model"
 	"github.com/Aditya-Chowdhary/micro-movies/movie/internal/controller/movie"
 )
 
@@ -22,7 +23,8 @@ func New(ctrl *movie.Controller) *Handler {
 // GetMovieDetails handles GET /movie requests.
 func (h *Handler) GetMovieDetails(w http.ResponseWriter, r *http.Request) {
 	id := r.FormValue("id")
-	details, err := h.ctrl.Get(r.Context(), id)
+	locales := model.ParseAcceptLanguage(r.Header.Get("Accept-Language"))
+	details, err := h.ctrl.Get(r.Context(), id, locales...)
 	if err != nil && errors.Is(err, movie.ErrNotFound) {
 		w.WriteHeader(http.StatusNotFound)
 		return
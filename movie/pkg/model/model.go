@@ -1,8 +1,68 @@
 package model
 
-import "github.com/Aditya-Chowdhary/micro-movies/metadata/pkg/model"
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/Aditya-Chowdhary/micro-movies/metadata/pkg/model"
+)
+
+// Components of movie details that can be degraded.
+const (
+	ComponentMetadata = "metadata"
+	ComponentRating   = "rating"
+)
+
+// ComponentStatus is the outcome of fetching one component of movie details.
+type ComponentStatus string
+
+const (
+	StatusOK          ComponentStatus = "ok"
+	StatusNotFound    ComponentStatus = "not_found"
+	StatusUnavailable ComponentStatus = "unavailable"
+)
+
+// Status reports the outcome of fetching each component of movie details.
+type Status struct {
+	Metadata ComponentStatus `json:"metadata"`
+	// Rating is StatusNotFound when the movie has no ratings yet and
+	// StatusUnavailable when the rating service could not be reached.
+	Rating ComponentStatus `json:"rating"`
+}
 
 type MovieDetails struct {
 	Rating   *float64       `json:"rating,omitempty"`
 	Metadata model.Metadata `json:"metadata"`
+	// Stale is set when downstream calls failed and the last known good
+	// details are served instead.
+	Stale bool `json:"stale,omitempty"`
+	// Age is how old stale details are, in seconds when encoded as JSON.
+	Age time.Duration `json:"age,omitempty"`
+	// Degraded lists the components whose latest data could not be fetched.
+	Degraded []string `json:"degraded,omitempty"`
+	Status   Status   `json:"status"`
+}
+
+// detailsJSON is the JSON encoding of MovieDetails, with the age in seconds.
+type detailsJSON struct {
+	movieDetails
+	Age float64 `json:"age,omitempty"`
+}
+
+type movieDetails MovieDetails
+
+// MarshalJSON encodes movie details with their age in seconds.
+func (d MovieDetails) MarshalJSON() ([]byte, error) {
+	return json.Marshal(detailsJSON{movieDetails(d), d.Age.Seconds()})
+}
+
+// UnmarshalJSON decodes movie details encoded by MarshalJSON.
+func (d *MovieDetails) UnmarshalJSON(data []byte) error {
+	var v detailsJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*d = MovieDetails(v.movieDetails)
+	d.Age = time.Duration(v.Age * float64(time.Second))
+	return nil
 }
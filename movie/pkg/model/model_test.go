@@ -0,0 +1,39 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/Aditya-Chowdhary/micro-movies/metadata/pkg/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMovieDetailsJSON(t *testing.T) {
+	rating := 4.5
+	details := MovieDetails{
+		Rating:   &rating,
+		Metadata: model.Metadata{ID: "1", Title: "Title"},
+		Stale:    true,
+		Age:      90 * time.Second,
+		Degraded: []string{ComponentRating},
+		Status:   Status{Metadata: StatusOK, Rating: StatusUnavailable},
+	}
+
+	data, err := json.Marshal(details)
+	require.NoError(t, err)
+	var fields map[string]any
+	require.NoError(t, json.Unmarshal(data, &fields))
+	assert.Equal(t, 90.0, fields["age"], "age is encoded in seconds")
+	assert.Equal(t, true, fields["stale"])
+
+	var got MovieDetails
+	require.NoError(t, json.Unmarshal(data, &got))
+	assert.Equal(t, details, got)
+
+	data, err = json.Marshal(MovieDetails{})
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "age", "fresh details have no age")
+}
@@ -2,17 +2,23 @@ package testutil
 
 import (
 	"github.com/Aditya-Chowdhary/micro-movies/gen"
+	"github.com/Aditya-Chowdhary/micro-movies/internal/grpcutil"
 	"github.com/Aditya-Chowdhary/micro-movies/movie/internal/controller/movie"
 	metadatagateway "github.com/Aditya-Chowdhary/micro-movies/movie/internal/gateway/metadata/grpc"
 	ratinggateway "github.com/Aditya-Chowdhary/micro-movies/movie/internal/gateway/rating/grpc"
+	"github.com/Aditya-Chowdhary/micro-movies/movie/internal/gateway/routing"
 	grpchandler "github.com/Aditya-Chowdhary/micro-movies/movie/internal/handler/grpc"
 	"github.com/Aditya-Chowdhary/micro-movies/pkg/discovery"
+
+	"github.com/uber-go/tally"
 )
 
 // NewTestMovieGRPCServer creates a new movie gRPC server for tests
 func NewTestMovieGRPCServer(registry discovery.Registry) gen.MovieServiceServer {
-	metadataGateway := metadatagateway.New(registry)
-	ratingGateway := ratinggateway.New(registry)
-	ctrl := movie.New(ratingGateway, metadataGateway)
+	conns := grpcutil.NewConnManager(registry)
+	router := routing.New(conns, tally.NoopScope)
+	metadataGateway := metadatagateway.New(router)
+	ratingGateway := ratinggateway.New(router)
+	ctrl := movie.New(ratingGateway, metadataGateway, movie.DefaultMaxStaleAge)
 	return grpchandler.New(ctrl)
 }
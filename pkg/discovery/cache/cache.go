@@ -0,0 +1,143 @@
+// Package cache provides a service registry serving address lookups from
+// watched snapshots instead of querying the underlying registry every time.
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Aditya-Chowdhary/micro-movies/pkg/discovery"
+)
+
+var (
+	errClosed     = errors.New("registry cache is closed")
+	errNoSnapshot = errors.New("no instances received from the registry yet")
+)
+
+// defaultInitialWait bounds how long a lookup waits for the first snapshot of
+// a service. Watches retry failed queries without reporting them, so lookups
+// would otherwise hang while the registry is unreachable.
+const defaultInitialWait = 5 * time.Second
+
+// Registry wraps a registry, serving ServiceAddresses from the latest
+// snapshot delivered by Watch. A service is watched from its first lookup
+// until the registry is closed.
+type Registry struct {
+	discovery.Registry
+
+	ctx         context.Context
+	cancel      context.CancelFunc
+	initialWait time.Duration
+
+	mu       sync.Mutex
+	services map[string]*snapshot
+}
+
+// snapshot is the latest known instance set of a service.
+type snapshot struct {
+	ready     chan struct{}
+	instances []discovery.Instance
+	err       error
+}
+
+// New creates a new caching registry in front of next.
+func New(next discovery.Registry) *Registry {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Registry{
+		Registry:    next,
+		ctx:         ctx,
+		cancel:      cancel,
+		initialWait: defaultInitialWait,
+		services:    map[string]*snapshot{},
+	}
+}
+
+// ServiceAddresses returns the latest known addresses of active instances of
+// given service. The first lookups of a service wait a few seconds at most for
+// its initial snapshot.
+func (r *Registry) ServiceAddresses(ctx context.Context, serviceName string) ([]string, error) {
+	instances, err := r.ServiceInstances(ctx, serviceName, nil)
+	if err != nil {
+		return nil, err
+	}
+	return discovery.Addresses(instances), nil
+}
+
+// ServiceInstances returns the latest known active instances of given service
+// matching filter. The first lookups of a service wait a few seconds at most
+// for its initial snapshot.
+func (r *Registry) ServiceInstances(ctx context.Context, serviceName string, filter map[string]string) ([]discovery.Instance, error) {
+	s := r.snapshot(serviceName)
+	timer := time.NewTimer(r.initialWait)
+	defer timer.Stop()
+	select {
+	case <-s.ready:
+	case <-timer.C:
+		return nil, fmt.Errorf("%w: service %s", errNoSnapshot, serviceName)
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if s.err != nil {
+		return nil, s.err
+	}
+	res := discovery.Filter(s.instances, filter)
+	if len(res) == 0 {
+		return nil, discovery.ErrNotFound
+	}
+	return res, nil
+}
+
+// snapshot returns the snapshot of serviceName, starting to watch it if needed.
+func (r *Registry) snapshot(serviceName string) *snapshot {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if s, ok := r.services[serviceName]; ok {
+		return s
+	}
+	s := &snapshot{ready: make(chan struct{})}
+	if r.ctx.Err() != nil {
+		s.err = errClosed
+		close(s.ready)
+		return s
+	}
+	r.services[serviceName] = s
+
+	ch, err := r.Registry.Watch(r.ctx, serviceName)
+	if err != nil {
+		// Do not cache the failure; the next lookup watches again.
+		delete(r.services, serviceName)
+		s.err = err
+		close(s.ready)
+		return s
+	}
+	go func() {
+		first := true
+		for instances := range ch {
+			r.mu.Lock()
+			s.instances = instances
+			r.mu.Unlock()
+			if first {
+				close(s.ready)
+				first = false
+			}
+		}
+		if first {
+			r.mu.Lock()
+			s.err = errClosed
+			r.mu.Unlock()
+			close(s.ready)
+		}
+	}()
+	return s
+}
+
+// Close stops watching services.
+func (r *Registry) Close() {
+	r.cancel()
+}
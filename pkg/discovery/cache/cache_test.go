@@ -0,0 +1,83 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Aditya-Chowdhary/micro-movies/pkg/discovery"
+	"github.com/Aditya-Chowdhary/micro-movies/pkg/discovery/memory"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingRegistry counts the lookups reaching the wrapped registry.
+type countingRegistry struct {
+	*memory.Registry
+	lookups int
+}
+
+func (r *countingRegistry) ServiceAddresses(ctx context.Context, serviceName string) ([]string, error) {
+	r.lookups++
+	return r.Registry.ServiceAddresses(ctx, serviceName)
+}
+
+func TestRegistry(t *testing.T) {
+	ctx := context.Background()
+	next := &countingRegistry{Registry: memory.NewRegistry()}
+	defer next.Close()
+	r := New(next)
+	defer r.Close()
+
+	_, err := r.ServiceAddresses(ctx, "svc")
+	assert.ErrorIs(t, err, discovery.ErrNotFound)
+
+	require.NoError(t, r.Register(ctx, "a", "svc", "localhost:1", nil))
+	require.Eventually(t, func() bool {
+		addrs, err := r.ServiceAddresses(ctx, "svc")
+		return err == nil && assert.ObjectsAreEqual([]string{"localhost:1"}, addrs)
+	}, time.Second, time.Millisecond)
+
+	require.NoError(t, r.Deregister(ctx, "a", "svc"))
+	require.Eventually(t, func() bool {
+		_, err := r.ServiceAddresses(ctx, "svc")
+		return err == discovery.ErrNotFound
+	}, time.Second, time.Millisecond)
+	assert.Zero(t, next.lookups, "lookups are served from watched snapshots")
+}
+
+func TestRegistryClosed(t *testing.T) {
+	next := memory.NewRegistry()
+	defer next.Close()
+	r := New(next)
+	r.Close()
+	_, err := r.ServiceAddresses(context.Background(), "svc")
+	assert.ErrorIs(t, err, errClosed)
+}
+
+// silentRegistry never delivers a snapshot, like a Consul registry retrying
+// queries against an unreachable agent.
+type silentRegistry struct {
+	discovery.Registry
+}
+
+func (silentRegistry) Watch(ctx context.Context, _ string) (<-chan []discovery.Instance, error) {
+	ch := make(chan []discovery.Instance)
+	go func() {
+		<-ctx.Done()
+		close(ch)
+	}()
+	return ch, nil
+}
+
+func TestRegistryInitialWait(t *testing.T) {
+	r := New(silentRegistry{})
+	defer r.Close()
+	r.initialWait = 10 * time.Millisecond
+
+	start := time.Now()
+	_, err := r.ServiceAddresses(context.Background(), "svc")
+	assert.ErrorIs(t, err, errNoSnapshot)
+	assert.Less(t, time.Since(start), time.Second, "lookups without a deadline do not hang")
+}
@@ -4,8 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/Aditya-Chowdhary/micro-movies/pkg/discovery"
 
@@ -27,8 +29,9 @@ func NewRegistry(addr string) (*Registry, error) {
 	return &Registry{client: client}, nil
 }
 
-// Register creates a service record in the registry.
-func (r *Registry) Register(ctx context.Context, instanceID string, serviceName string, hostPort string) error {
+// Register creates a service record in the registry. Metadata is stored as
+// Consul service meta and mirrored as key=value tags for filtering in Consul.
+func (r *Registry) Register(ctx context.Context, instanceID string, serviceName string, hostPort string, metadata map[string]string) error {
 	parts := strings.Split(hostPort, ":")
 	if len(parts) != 2 {
 		return errors.New("hostPort must be in form <host>:<port>, example: localhost:8081")
@@ -38,15 +41,40 @@ func (r *Registry) Register(ctx context.Context, instanceID string, serviceName
 		return err
 	}
 
+	if err := r.deregisterStale(instanceID, serviceName, parts[0], port); err != nil {
+		return err
+	}
 	return r.client.Agent().ServiceRegister(&consul.AgentServiceRegistration{
 		Address: parts[0],
 		ID:      instanceID,
 		Name:    serviceName,
 		Port:    port,
-		Check:   &consul.AgentServiceCheck{CheckID: instanceID, TTL: "5s"},
+		Tags:    tags(metadata),
+		Meta:    metadata,
+		Check:   &consul.AgentServiceCheck{CheckID: instanceID, TTL: discovery.DefaultTTL.String()},
 	})
 }
 
+// deregisterStale removes the records of serviceName registered by previous
+// instances on the same host and port, which would otherwise linger as
+// critical until Consul reaps them.
+func (r *Registry) deregisterStale(instanceID string, serviceName string, host string, port int) error {
+	filter := fmt.Sprintf("Service == %q and Address == %q and Port == %d", serviceName, host, port)
+	services, err := r.client.Agent().ServicesWithFilter(filter)
+	if err != nil {
+		return err
+	}
+	for id := range services {
+		if id == instanceID {
+			continue
+		}
+		if err := r.client.Agent().ServiceDeregister(id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Deregister removes a service record from the registry
 func (r *Registry) Deregister(ctx context.Context, instanceID string, serviceName string) error {
 	return r.client.Agent().ServiceDeregister(instanceID)
@@ -68,7 +96,96 @@ func (r *Registry) ServiceAddresses(ctx context.Context, serviceName string) ([]
 	return res, nil
 }
 
+// ServiceInstances returns the active instances of given service matching filter.
+func (r *Registry) ServiceInstances(ctx context.Context, serviceName string, filter map[string]string) ([]discovery.Instance, error) {
+	entries, _, err := r.client.Health().ServiceMultipleTags(serviceName, tags(filter), true, (&consul.QueryOptions{}).WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	res := discovery.Filter(instances(entries), filter)
+	if len(res) == 0 {
+		return nil, discovery.ErrNotFound
+	}
+	return res, nil
+}
+
+func instances(entries []*consul.ServiceEntry) []discovery.Instance {
+	res := make([]discovery.Instance, 0, len(entries))
+	for _, e := range entries {
+		res = append(res, discovery.Instance{
+			ID:       e.Service.ID,
+			HostPort: fmt.Sprintf("%s:%d", e.Service.Address, e.Service.Port),
+			Metadata: e.Service.Meta,
+		})
+	}
+	discovery.SortInstances(res)
+	return res
+}
+
+// tags returns metadata as sorted key=value tags.
+func tags(metadata map[string]string) []string {
+	var res []string
+	for k, v := range metadata {
+		res = append(res, k+"="+v)
+	}
+	slices.Sort(res)
+	return res
+}
+
 // ReportHealthyState is a push mechanism for reporting healthy state to the registry
 func (r *Registry) ReportHealthyState(instanceID string, _ string) error {
 	return r.client.Agent().PassTTL(instanceID, "")
 }
+
+const (
+	// watchWait is how long a blocking query waits for changes before returning.
+	watchWait = 5 * time.Minute
+	// maxWatchBackoff caps the delay between failed blocking queries.
+	maxWatchBackoff = 30 * time.Second
+)
+
+// Watch delivers the active instances of serviceName whenever
+// they change, using Consul blocking queries. Failed queries are retried with
+// exponential backoff.
+func (r *Registry) Watch(ctx context.Context, serviceName string) (<-chan []discovery.Instance, error) {
+	ch := make(chan []discovery.Instance)
+	go func() {
+		defer close(ch)
+		var index uint64
+		var last []discovery.Instance
+		first := true
+		backoff := time.Second
+		for {
+			opts := (&consul.QueryOptions{WaitIndex: index, WaitTime: watchWait}).WithContext(ctx)
+			entries, meta, err := r.client.Health().Service(serviceName, "", true, opts)
+			if err != nil {
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(backoff):
+				}
+				backoff = min(2*backoff, maxWatchBackoff)
+				continue
+			}
+			backoff = time.Second
+			// The index going backwards means the agent was reset; start over.
+			if meta.LastIndex < index {
+				index = 0
+			} else {
+				index = meta.LastIndex
+			}
+
+			current := instances(entries)
+			if !first && slices.EqualFunc(current, last, discovery.Instance.Equal) {
+				continue
+			}
+			select {
+			case ch <- current:
+			case <-ctx.Done():
+				return
+			}
+			last, first = current, false
+		}
+	}()
+	return ch, nil
+}
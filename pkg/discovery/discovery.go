@@ -1,22 +1,129 @@
 package discovery
 
 import (
+	"cmp"
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
 	"fmt"
-	"math/rand"
-	"time"
+	"maps"
+	"net"
+	"os"
+	"slices"
 )
 
 type Registry interface {
-	Register(ctx context.Context, instanceID string, serviceName string, hostPort string) error
+	// Register creates a service record advertising metadata, which may be nil.
+	Register(ctx context.Context, instanceID string, serviceName string, hostPort string, metadata map[string]string) error
 	Deregister(ctx context.Context, instanceID string, serviceName string) error
 	ServiceAddresses(ctx context.Context, serviceName string) ([]string, error)
+	// ServiceInstances returns the active instances of serviceName whose
+	// metadata contains every key and value of filter, or ErrNotFound if
+	// there are none.
+	ServiceInstances(ctx context.Context, serviceName string, filter map[string]string) ([]Instance, error)
 	ReportHealthyState(instanceID string, serviceName string) error
+	// Watch delivers the active instances of serviceName, starting with the
+	// current set and then every time it changes. An empty set means there
+	// are no active instances. The channel is closed once ctx is done.
+	Watch(ctx context.Context, serviceName string) (<-chan []Instance, error)
 }
 
 var ErrNotFound = errors.New("no service address found")
 
-func GenerateInstanceID(serviceName string) string {
-	return fmt.Sprintf("%s-%d", serviceName, rand.New(rand.NewSource(time.Now().UnixNano())).Int())
+// Well-known instance metadata keys.
+const (
+	// MetadataProtocol is the protocol the instance serves, e.g. grpc or http.
+	MetadataProtocol = "protocol"
+	// MetadataVersion is the version of the service the instance runs.
+	MetadataVersion = "version"
+	// MetadataZone is the availability zone the instance runs in.
+	MetadataZone = "zone"
+	// MetadataWeight is the relative share of traffic the instance should get.
+	MetadataWeight = "weight"
+)
+
+// Instance is an active instance of a service.
+type Instance struct {
+	ID       string
+	HostPort string
+	Metadata map[string]string
+}
+
+// Matches reports whether the instance metadata contains every key and value of filter.
+func (i Instance) Matches(filter map[string]string) bool {
+	for k, v := range filter {
+		if got, ok := i.Metadata[k]; !ok || got != v {
+			return false
+		}
+	}
+	return true
+}
+
+// Equal reports whether i and o are the same instance with the same metadata.
+func (i Instance) Equal(o Instance) bool {
+	return i.ID == o.ID && i.HostPort == o.HostPort && maps.Equal(i.Metadata, o.Metadata)
+}
+
+// SortInstances sorts instances by address and ID, so sets can be compared.
+func SortInstances(instances []Instance) {
+	slices.SortFunc(instances, func(a, b Instance) int {
+		if c := cmp.Compare(a.HostPort, b.HostPort); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.ID, b.ID)
+	})
+}
+
+// Filter returns the instances matching filter.
+func Filter(instances []Instance, filter map[string]string) []Instance {
+	var res []Instance
+	for _, i := range instances {
+		if i.Matches(filter) {
+			res = append(res, i)
+		}
+	}
+	return res
+}
+
+// PreferZone returns the instances in zone if there are at least minInstances
+// of them, and otherwise all instances so calls spill over to other zones.
+// An empty zone prefers no zone.
+func PreferZone(instances []Instance, zone string, minInstances int) []Instance {
+	if zone == "" {
+		return instances
+	}
+	local := Filter(instances, map[string]string{MetadataZone: zone})
+	if len(local) >= max(minInstances, 1) {
+		return local
+	}
+	return instances
+}
+
+// Addresses returns the addresses of instances.
+func Addresses(instances []Instance) []string {
+	res := make([]string, 0, len(instances))
+	for _, i := range instances {
+		res = append(res, i.HostPort)
+	}
+	return res
+}
+
+// GenerateInstanceID returns an ID for an instance of serviceName listening
+// on hostPort, made of the service name, the hostname, the port and a random
+// suffix, so that instances starting together do not collide.
+func GenerateInstanceID(serviceName string, hostPort string) (string, error) {
+	_, port, err := net.SplitHostPort(hostPort)
+	if err != nil {
+		return "", err
+	}
+	hostname, err := os.Hostname()
+	if err != nil {
+		return "", err
+	}
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s-%s-%s-%s", serviceName, hostname, port, hex.EncodeToString(suffix)), nil
 }
@@ -0,0 +1,52 @@
+package discovery
+
+import (
+	"os"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreferZone(t *testing.T) {
+	instances := []Instance{
+		{ID: "a1", HostPort: "a1:1", Metadata: map[string]string{MetadataZone: "a"}},
+		{ID: "a2", HostPort: "a2:1", Metadata: map[string]string{MetadataZone: "a"}},
+		{ID: "b1", HostPort: "b1:1", Metadata: map[string]string{MetadataZone: "b"}},
+		{ID: "none", HostPort: "none:1"},
+	}
+	tests := []struct {
+		name         string
+		zone         string
+		minInstances int
+		want         []string
+	}{
+		{name: "no zone", zone: "", want: []string{"a1:1", "a2:1", "b1:1", "none:1"}},
+		{name: "local", zone: "a", minInstances: 2, want: []string{"a1:1", "a2:1"}},
+		{name: "default minimum", zone: "b", want: []string{"b1:1"}},
+		{name: "spill over", zone: "b", minInstances: 2, want: []string{"a1:1", "a2:1", "b1:1", "none:1"}},
+		{name: "unknown zone", zone: "c", want: []string{"a1:1", "a2:1", "b1:1", "none:1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Addresses(PreferZone(instances, tt.zone, tt.minInstances)))
+		})
+	}
+}
+
+func TestGenerateInstanceID(t *testing.T) {
+	hostname, err := os.Hostname()
+	require.NoError(t, err)
+
+	id, err := GenerateInstanceID("rating", "localhost:8082")
+	require.NoError(t, err)
+	assert.Regexp(t, "^"+regexp.QuoteMeta("rating-"+hostname+"-8082-")+"[0-9a-f]{8}$", id)
+
+	other, err := GenerateInstanceID("rating", "localhost:8082")
+	require.NoError(t, err)
+	assert.NotEqual(t, id, other, "instances on the same host and port get distinct IDs")
+
+	_, err = GenerateInstanceID("rating", "localhost")
+	assert.Error(t, err)
+}
@@ -0,0 +1,148 @@
+// Package dns implements a service registry resolving instances from DNS SRV
+// records, for environments providing DNS-based discovery.
+package dns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Aditya-Chowdhary/micro-movies/pkg/discovery"
+)
+
+// DefaultRefreshInterval is how often watched services are looked up again.
+const DefaultRefreshInterval = 30 * time.Second
+
+// Options configures a DNS registry.
+type Options struct {
+	// Domain is the domain service records live in. The instances of a
+	// service are the SRV records of _<service>._<proto>.<domain>.
+	Domain string
+	// Proto is the protocol of the SRV records. Defaults to tcp.
+	Proto string
+	// RefreshInterval is how often watched services are looked up again.
+	RefreshInterval time.Duration
+}
+
+// lookupSRVFunc looks up SRV records like net.Resolver.LookupSRV.
+type lookupSRVFunc func(ctx context.Context, service, proto, name string) (string, []*net.SRV, error)
+
+// Registry is a service registry resolving instances from DNS SRV records.
+// Registration is managed by DNS: Register, Deregister and
+// ReportHealthyState do nothing.
+type Registry struct {
+	opts      Options
+	lookupSRV lookupSRVFunc
+}
+
+// NewRegistry creates a new DNS registry using the system resolver.
+func NewRegistry(opts Options) (*Registry, error) {
+	if opts.Domain == "" {
+		return nil, errors.New("dns registry domain is required")
+	}
+	if opts.Proto == "" {
+		opts.Proto = "tcp"
+	}
+	if opts.RefreshInterval <= 0 {
+		opts.RefreshInterval = DefaultRefreshInterval
+	}
+	return &Registry{opts: opts, lookupSRV: net.DefaultResolver.LookupSRV}, nil
+}
+
+// Register does nothing; instances are published in DNS.
+func (r *Registry) Register(ctx context.Context, instanceID string, serviceName string, hostPort string, metadata map[string]string) error {
+	return nil
+}
+
+// Deregister does nothing; instances are published in DNS.
+func (r *Registry) Deregister(ctx context.Context, instanceID string, serviceName string) error {
+	return nil
+}
+
+// ReportHealthyState does nothing; DNS only publishes active instances.
+func (r *Registry) ReportHealthyState(instanceID string, serviceName string) error {
+	return nil
+}
+
+// ServiceAddresses returns the addresses of the instances of given service.
+func (r *Registry) ServiceAddresses(ctx context.Context, serviceName string) ([]string, error) {
+	instances, err := r.ServiceInstances(ctx, serviceName, nil)
+	if err != nil {
+		return nil, err
+	}
+	return discovery.Addresses(instances), nil
+}
+
+// ServiceInstances returns the instances of given service matching filter.
+// The SRV record weight is exposed as the weight metadata of an instance.
+func (r *Registry) ServiceInstances(ctx context.Context, serviceName string, filter map[string]string) ([]discovery.Instance, error) {
+	instances, err := r.lookup(ctx, serviceName)
+	if err != nil {
+		return nil, err
+	}
+	res := discovery.Filter(instances, filter)
+	if len(res) == 0 {
+		return nil, discovery.ErrNotFound
+	}
+	return res, nil
+}
+
+// lookup returns the instances of serviceName, which is empty if there are none.
+func (r *Registry) lookup(ctx context.Context, serviceName string) ([]discovery.Instance, error) {
+	_, records, err := r.lookupSRV(ctx, serviceName, r.opts.Proto, r.opts.Domain)
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	res := make([]discovery.Instance, 0, len(records))
+	for _, srv := range records {
+		hostPort := net.JoinHostPort(strings.TrimSuffix(srv.Target, "."), strconv.Itoa(int(srv.Port)))
+		res = append(res, discovery.Instance{
+			ID:       fmt.Sprintf("%s-%s", serviceName, hostPort),
+			HostPort: hostPort,
+			Metadata: map[string]string{discovery.MetadataWeight: strconv.Itoa(int(srv.Weight))},
+		})
+	}
+	discovery.SortInstances(res)
+	return res, nil
+}
+
+// Watch delivers the instances of serviceName, looking them up again every
+// RefreshInterval. Failed lookups keep the last delivered instances.
+func (r *Registry) Watch(ctx context.Context, serviceName string) (<-chan []discovery.Instance, error) {
+	ch := make(chan []discovery.Instance)
+	go func() {
+		defer close(ch)
+		ticker := time.NewTicker(r.opts.RefreshInterval)
+		defer ticker.Stop()
+
+		var last []discovery.Instance
+		first := true
+		for {
+			instances, err := r.lookup(ctx, serviceName)
+			if err == nil && (first || !slices.EqualFunc(instances, last, discovery.Instance.Equal)) {
+				select {
+				case ch <- instances:
+				case <-ctx.Done():
+					return
+				}
+				last, first = instances, false
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return ch, nil
+}
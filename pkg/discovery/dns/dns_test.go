@@ -0,0 +1,100 @@
+package dns
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Aditya-Chowdhary/micro-movies/pkg/discovery"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeDNS serves SRV records from memory.
+type fakeDNS struct {
+	mu      sync.Mutex
+	records map[string][]*net.SRV
+	err     error
+}
+
+func (f *fakeDNS) lookupSRV(_ context.Context, service, proto, name string) (string, []*net.SRV, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	cname := "_" + service + "._" + proto + "." + name
+	if f.err != nil {
+		return "", nil, f.err
+	}
+	records, ok := f.records[cname]
+	if !ok {
+		return "", nil, &net.DNSError{Err: "no such host", Name: cname, IsNotFound: true}
+	}
+	return cname, records, nil
+}
+
+func (f *fakeDNS) set(cname string, records []*net.SRV, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.records[cname] = records
+	f.err = err
+}
+
+func newTestRegistry(t *testing.T, f *fakeDNS) *Registry {
+	r, err := NewRegistry(Options{Domain: "service.local", RefreshInterval: 10 * time.Millisecond})
+	require.NoError(t, err)
+	r.lookupSRV = f.lookupSRV
+	return r
+}
+
+func TestNewRegistry(t *testing.T) {
+	_, err := NewRegistry(Options{})
+	assert.Error(t, err, "domain is required")
+}
+
+func TestServiceInstances(t *testing.T) {
+	ctx := context.Background()
+	f := &fakeDNS{records: map[string][]*net.SRV{
+		"_rating._tcp.service.local": {
+			{Target: "rating-2.service.local.", Port: 8082, Weight: 10},
+			{Target: "rating-1.service.local.", Port: 8082, Weight: 90},
+		},
+	}}
+	r := newTestRegistry(t, f)
+
+	addrs, err := r.ServiceAddresses(ctx, "rating")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"rating-1.service.local:8082", "rating-2.service.local:8082"}, addrs)
+
+	heavy, err := r.ServiceInstances(ctx, "rating", map[string]string{discovery.MetadataWeight: "90"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"rating-1.service.local:8082"}, discovery.Addresses(heavy))
+
+	_, err = r.ServiceAddresses(ctx, "metadata")
+	assert.ErrorIs(t, err, discovery.ErrNotFound)
+}
+
+func TestWatch(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	f := &fakeDNS{records: map[string][]*net.SRV{
+		"_rating._tcp.service.local": {{Target: "rating-1.service.local.", Port: 8082}},
+	}}
+	r := newTestRegistry(t, f)
+
+	ch, err := r.Watch(ctx, "rating")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"rating-1.service.local:8082"}, discovery.Addresses(<-ch))
+
+	// Failed lookups keep the last instances.
+	f.set("_rating._tcp.service.local", nil, errors.New("timeout"))
+	time.Sleep(50 * time.Millisecond)
+	f.set("_rating._tcp.service.local", []*net.SRV{{Target: "rating-2.service.local.", Port: 8082}}, nil)
+	assert.Equal(t, []string{"rating-2.service.local:8082"}, discovery.Addresses(<-ch))
+
+	cancel()
+	for range ch {
+	}
+}
@@ -0,0 +1,224 @@
+// Package file implements a service registry with a fixed topology read from
+// a YAML or JSON file, reloaded whenever the file changes.
+package file
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/Aditya-Chowdhary/micro-movies/pkg/discovery"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultReloadInterval is how often the file is checked for changes.
+const DefaultReloadInterval = time.Second
+
+// Topology is the content of a registry file: the instances of each service,
+// keyed by service name. JSON files use the same field names.
+type Topology struct {
+	Services map[string][]InstanceConfig `yaml:"services"`
+}
+
+// InstanceConfig is an instance of a service in a registry file.
+type InstanceConfig struct {
+	ID       string            `yaml:"id"`
+	Address  string            `yaml:"address"`
+	Metadata map[string]string `yaml:"metadata"`
+}
+
+// Registry is a service registry serving the instances listed in a file.
+// The file is the only source of truth: Register, Deregister and
+// ReportHealthyState do nothing and every listed instance is active.
+type Registry struct {
+	path     string
+	interval time.Duration
+	cancel   context.CancelFunc
+	done     chan struct{}
+
+	mu       sync.RWMutex
+	content  []byte
+	services map[string][]discovery.Instance
+	watchers map[string]map[chan struct{}]struct{}
+}
+
+// NewRegistry creates a new registry from the file at path, checking it for
+// changes every interval. Close stops checking.
+func NewRegistry(path string, interval time.Duration) (*Registry, error) {
+	if interval <= 0 {
+		interval = DefaultReloadInterval
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	r := &Registry{
+		path:     path,
+		interval: interval,
+		cancel:   cancel,
+		done:     make(chan struct{}),
+		watchers: map[string]map[chan struct{}]struct{}{},
+	}
+	if err := r.reload(); err != nil {
+		cancel()
+		return nil, err
+	}
+	go r.run(ctx)
+	return r, nil
+}
+
+// Close stops checking the file for changes.
+func (r *Registry) Close() {
+	r.cancel()
+	<-r.done
+}
+
+func (r *Registry) run(ctx context.Context) {
+	defer close(r.done)
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			// An invalid file keeps the last valid topology until it is fixed.
+			r.reload()
+		}
+	}
+}
+
+// reload reads the file and replaces the topology if it changed.
+func (r *Registry) reload() error {
+	content, err := os.ReadFile(r.path)
+	if err != nil {
+		return err
+	}
+	r.mu.RLock()
+	unchanged := r.services != nil && bytes.Equal(content, r.content)
+	r.mu.RUnlock()
+	if unchanged {
+		return nil
+	}
+
+	services, err := parse(content)
+	if err != nil {
+		return fmt.Errorf("parse %s: %w", r.path, err)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.content = content
+	r.services = services
+	for _, watchers := range r.watchers {
+		for notify := range watchers {
+			select {
+			case notify <- struct{}{}:
+			default:
+			}
+		}
+	}
+	return nil
+}
+
+func parse(content []byte) (map[string][]discovery.Instance, error) {
+	var t Topology
+	if err := yaml.Unmarshal(content, &t); err != nil {
+		return nil, err
+	}
+	services := map[string][]discovery.Instance{}
+	for name, instances := range t.Services {
+		for _, i := range instances {
+			if i.Address == "" {
+				return nil, fmt.Errorf("instance of %s is missing an address", name)
+			}
+			id := i.ID
+			if id == "" {
+				id = name + "-" + i.Address
+			}
+			services[name] = append(services[name], discovery.Instance{ID: id, HostPort: i.Address, Metadata: i.Metadata})
+		}
+		discovery.SortInstances(services[name])
+	}
+	return services, nil
+}
+
+// Register does nothing; instances are listed in the file.
+func (r *Registry) Register(ctx context.Context, instanceID string, serviceName string, hostPort string, metadata map[string]string) error {
+	return nil
+}
+
+// Deregister does nothing; instances are listed in the file.
+func (r *Registry) Deregister(ctx context.Context, instanceID string, serviceName string) error {
+	return nil
+}
+
+// ReportHealthyState does nothing; listed instances are always active.
+func (r *Registry) ReportHealthyState(instanceID string, serviceName string) error {
+	return nil
+}
+
+// ServiceAddresses returns the addresses of the instances of given service.
+func (r *Registry) ServiceAddresses(ctx context.Context, serviceName string) ([]string, error) {
+	instances, err := r.ServiceInstances(ctx, serviceName, nil)
+	if err != nil {
+		return nil, err
+	}
+	return discovery.Addresses(instances), nil
+}
+
+// ServiceInstances returns the instances of given service matching filter.
+func (r *Registry) ServiceInstances(ctx context.Context, serviceName string, filter map[string]string) ([]discovery.Instance, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	res := discovery.Filter(r.services[serviceName], filter)
+	if len(res) == 0 {
+		return nil, discovery.ErrNotFound
+	}
+	return res, nil
+}
+
+// Watch delivers the instances of serviceName whenever the file changes them.
+func (r *Registry) Watch(ctx context.Context, serviceName string) (<-chan []discovery.Instance, error) {
+	notify := make(chan struct{}, 1)
+	r.mu.Lock()
+	if r.watchers[serviceName] == nil {
+		r.watchers[serviceName] = map[chan struct{}]struct{}{}
+	}
+	r.watchers[serviceName][notify] = struct{}{}
+	r.mu.Unlock()
+
+	ch := make(chan []discovery.Instance)
+	go func() {
+		defer close(ch)
+		defer func() {
+			r.mu.Lock()
+			delete(r.watchers[serviceName], notify)
+			r.mu.Unlock()
+		}()
+
+		var last []discovery.Instance
+		first := true
+		for {
+			r.mu.RLock()
+			instances := slices.Clone(r.services[serviceName])
+			r.mu.RUnlock()
+			if first || !slices.EqualFunc(instances, last, discovery.Instance.Equal) {
+				select {
+				case ch <- instances:
+				case <-ctx.Done():
+					return
+				}
+				last, first = instances, false
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-notify:
+			}
+		}
+	}()
+	return ch, nil
+}
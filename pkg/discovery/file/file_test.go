@@ -0,0 +1,70 @@
+package file
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Aditya-Chowdhary/micro-movies/pkg/discovery"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, path string, content string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+}
+
+func TestRegistry(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "registry.yaml")
+	writeFile(t, path, `
+services:
+  rating:
+    - id: rating-2
+      address: localhost:8092
+      metadata: {version: v2}
+    - address: localhost:8082
+`)
+	r, err := NewRegistry(path, 10*time.Millisecond)
+	require.NoError(t, err)
+	defer r.Close()
+
+	addrs, err := r.ServiceAddresses(ctx, "rating")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"localhost:8082", "localhost:8092"}, addrs)
+	v2, err := r.ServiceInstances(ctx, "rating", map[string]string{"version": "v2"})
+	require.NoError(t, err)
+	assert.Equal(t, []discovery.Instance{{ID: "rating-2", HostPort: "localhost:8092", Metadata: map[string]string{"version": "v2"}}}, v2)
+	_, err = r.ServiceAddresses(ctx, "metadata")
+	assert.ErrorIs(t, err, discovery.ErrNotFound)
+
+	ch, err := r.Watch(ctx, "rating")
+	require.NoError(t, err)
+	assert.Len(t, <-ch, 2)
+
+	// JSON is accepted too, and changes are picked up without a restart.
+	writeFile(t, path, `{"services": {"rating": [{"id": "rating-3", "address": "localhost:8093"}]}}`)
+	assert.Equal(t, []string{"localhost:8093"}, discovery.Addresses(<-ch))
+
+	// An invalid file keeps the last valid topology.
+	writeFile(t, path, `services: [`)
+	time.Sleep(50 * time.Millisecond)
+	addrs, err = r.ServiceAddresses(ctx, "rating")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"localhost:8093"}, addrs)
+}
+
+func TestNewRegistryInvalid(t *testing.T) {
+	dir := t.TempDir()
+	_, err := NewRegistry(filepath.Join(dir, "missing.yaml"), 0)
+	assert.Error(t, err)
+
+	path := filepath.Join(dir, "registry.yaml")
+	writeFile(t, path, "services:\n  rating:\n    - id: no-address\n")
+	_, err = NewRegistry(path, 0)
+	assert.Error(t, err)
+}
@@ -0,0 +1,131 @@
+package discovery
+
+import (
+	"context"
+	"math/rand"
+	"time"
+)
+
+// DefaultTTL is how long registries keep an instance healthy without a heartbeat.
+const DefaultTTL = 5 * time.Second
+
+const (
+	// maxRegisterBackoff caps the delay between failed registration attempts.
+	maxRegisterBackoff = 30 * time.Second
+	// deregisterTimeout bounds deregistration once the lifecycle is stopped.
+	deregisterTimeout = 5 * time.Second
+	// jitter is the fraction heartbeat intervals are randomly shortened or lengthened by.
+	jitter = 0.2
+)
+
+// Registration describes a service instance to keep registered.
+type Registration struct {
+	InstanceID  string
+	ServiceName string
+	HostPort    string
+	Metadata    map[string]string
+	// TTL is how long the registry keeps the instance healthy without a
+	// heartbeat. Heartbeats are sent three times per TTL. Defaults to DefaultTTL.
+	TTL time.Duration
+}
+
+// Lifecycle keeps a service instance registered: it registers the instance
+// once it is ready, reports its healthy state at an interval derived from the TTL, registers it
+// again if the registry loses it, and deregisters it when stopped.
+type Lifecycle struct {
+	registry Registry
+	reg      Registration
+
+	// OnError is called with registration and heartbeat failures, which are
+	// retried. It may be nil.
+	OnError func(err error)
+	// Ready reports whether the service is ready to serve. The instance is
+	// not registered until it is, and heartbeats are skipped while it is not,
+	// letting the registry mark the instance unhealthy. It may be nil.
+	Ready func() bool
+}
+
+// NewLifecycle creates a new lifecycle of reg in registry.
+func NewLifecycle(registry Registry, reg Registration) *Lifecycle {
+	if reg.TTL <= 0 {
+		reg.TTL = DefaultTTL
+	}
+	return &Lifecycle{registry: registry, reg: reg}
+}
+
+// Run registers the instance once it is ready and keeps it registered until
+// ctx is done, then deregisters it and returns the deregistration error.
+func (l *Lifecycle) Run(ctx context.Context) error {
+	interval := l.reg.TTL / 3
+	if !l.waitReady(ctx, interval) || !l.register(ctx, interval) {
+		return l.deregister(ctx)
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return l.deregister(ctx)
+		case <-time.After(withJitter(interval)):
+		}
+		if l.Ready != nil && !l.Ready() {
+			continue
+		}
+		if err := l.registry.ReportHealthyState(l.reg.InstanceID, l.reg.ServiceName); err != nil {
+			// The registry may have lost the instance, e.g. after a restart.
+			l.report(err)
+			if !l.register(ctx, interval) {
+				return l.deregister(ctx)
+			}
+		}
+	}
+}
+
+// waitReady polls Ready every interval until it reports true. It returns false
+// if ctx is done first.
+func (l *Lifecycle) waitReady(ctx context.Context, interval time.Duration) bool {
+	for l.Ready != nil && !l.Ready() {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(interval):
+		}
+	}
+	return true
+}
+
+// register registers the instance, retrying with exponential backoff. It
+// returns false if ctx is done first.
+func (l *Lifecycle) register(ctx context.Context, backoff time.Duration) bool {
+	for {
+		err := l.registry.Register(ctx, l.reg.InstanceID, l.reg.ServiceName, l.reg.HostPort, l.reg.Metadata)
+		if err == nil {
+			return true
+		}
+		if ctx.Err() != nil {
+			return false
+		}
+		l.report(err)
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(withJitter(backoff)):
+		}
+		backoff = min(2*backoff, maxRegisterBackoff)
+	}
+}
+
+func (l *Lifecycle) deregister(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), deregisterTimeout)
+	defer cancel()
+	return l.registry.Deregister(ctx, l.reg.InstanceID, l.reg.ServiceName)
+}
+
+func (l *Lifecycle) report(err error) {
+	if l.OnError != nil {
+		l.OnError(err)
+	}
+}
+
+// withJitter randomly shortens or lengthens d by up to jitter.
+func withJitter(d time.Duration) time.Duration {
+	return time.Duration(float64(d) * (1 + jitter*(2*rand.Float64()-1)))
+}
@@ -0,0 +1,193 @@
+package discovery
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRegistry records registrations and fails the configured number of
+// Register calls.
+type fakeRegistry struct {
+	Registry
+
+	mu            sync.Mutex
+	failRegister  int
+	registrations int
+	heartbeats    int
+	registered    bool
+	deregistered  bool
+}
+
+func (r *fakeRegistry) Register(ctx context.Context, instanceID string, serviceName string, hostPort string, metadata map[string]string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.failRegister > 0 {
+		r.failRegister--
+		return errors.New("registry unavailable")
+	}
+	r.registrations++
+	r.registered = true
+	return nil
+}
+
+func (r *fakeRegistry) Deregister(ctx context.Context, instanceID string, serviceName string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.registered = false
+	r.deregistered = true
+	return nil
+}
+
+func (r *fakeRegistry) ReportHealthyState(instanceID string, serviceName string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.registered {
+		return ErrNotFound
+	}
+	r.heartbeats++
+	return nil
+}
+
+// lose forgets the registration, like a restarted registry.
+func (r *fakeRegistry) lose() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.registered = false
+}
+
+func (r *fakeRegistry) state() (registrations, heartbeats int, registered, deregistered bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.registrations, r.heartbeats, r.registered, r.deregistered
+}
+
+// runLifecycle runs a lifecycle in r, which is ready when ready returns true,
+// and returns a function stopping it and one returning the number of reported
+// failures.
+func runLifecycle(t *testing.T, r *fakeRegistry, ready func() bool) (context.CancelFunc, <-chan error, func() int) {
+	t.Helper()
+	var mu sync.Mutex
+	failures := 0
+	l := NewLifecycle(r, Registration{
+		InstanceID:  "svc-1",
+		ServiceName: "svc",
+		HostPort:    "localhost:1",
+		TTL:         30 * time.Millisecond,
+	})
+	l.Ready = ready
+	l.OnError = func(error) {
+		mu.Lock()
+		defer mu.Unlock()
+		failures++
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- l.Run(ctx) }()
+	t.Cleanup(cancel)
+	return cancel, done, func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return failures
+	}
+}
+
+func TestLifecycle(t *testing.T) {
+	r := &fakeRegistry{}
+	cancel, done, _ := runLifecycle(t, r, nil)
+
+	require.Eventually(t, func() bool {
+		_, heartbeats, _, _ := r.state()
+		return heartbeats >= 3
+	}, time.Second, 5*time.Millisecond, "heartbeats are sent")
+
+	r.lose()
+	require.Eventually(t, func() bool {
+		registrations, _, registered, _ := r.state()
+		return registrations == 2 && registered
+	}, time.Second, 5*time.Millisecond, "a lost instance is registered again")
+
+	cancel()
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("Run did not return after cancellation")
+	}
+	_, _, registered, deregistered := r.state()
+	assert.False(t, registered)
+	assert.True(t, deregistered, "the instance is deregistered on cancellation")
+}
+
+func TestLifecycleRegisterRetry(t *testing.T) {
+	r := &fakeRegistry{failRegister: 2}
+	cancel, done, failures := runLifecycle(t, r, nil)
+
+	require.Eventually(t, func() bool {
+		_, heartbeats, _, _ := r.state()
+		return heartbeats > 0
+	}, time.Second, 5*time.Millisecond, "registration is retried until it succeeds")
+
+	cancel()
+	require.NoError(t, <-done)
+	registrations, _, _, deregistered := r.state()
+	assert.Equal(t, 1, registrations)
+	assert.Equal(t, 2, failures())
+	assert.True(t, deregistered)
+}
+
+func TestLifecycleNotReady(t *testing.T) {
+	r := &fakeRegistry{}
+	var ready atomic.Bool
+	cancel, done, _ := runLifecycle(t, r, ready.Load)
+
+	time.Sleep(50 * time.Millisecond)
+	registrations, _, _, _ := r.state()
+	assert.Zero(t, registrations, "an unready instance is not registered")
+
+	ready.Store(true)
+	require.Eventually(t, func() bool {
+		_, heartbeats, registered, _ := r.state()
+		return registered && heartbeats > 0
+	}, time.Second, 5*time.Millisecond, "the instance is registered once ready")
+
+	ready.Store(false)
+	time.Sleep(20 * time.Millisecond)
+	_, before, _, _ := r.state()
+	time.Sleep(50 * time.Millisecond)
+	_, heartbeats, registered, _ := r.state()
+	assert.Equal(t, before, heartbeats, "no heartbeats are sent while unready")
+	assert.True(t, registered)
+
+	cancel()
+	require.NoError(t, <-done)
+}
+
+func TestLifecycleStoppedBeforeReady(t *testing.T) {
+	r := &fakeRegistry{}
+	cancel, done, _ := runLifecycle(t, r, func() bool { return false })
+
+	cancel()
+	require.NoError(t, <-done)
+	registrations, _, _, deregistered := r.state()
+	assert.Zero(t, registrations)
+	assert.True(t, deregistered, "deregistering an unknown instance is harmless")
+}
+
+func TestWithJitter(t *testing.T) {
+	d := time.Second
+	for i := 0; i < 100; i++ {
+		got := withJitter(d)
+		assert.GreaterOrEqual(t, got, time.Duration(float64(d)*(1-jitter)))
+		assert.LessOrEqual(t, got, time.Duration(float64(d)*(1+jitter)))
+	}
+}
@@ -2,78 +2,317 @@ package memory
 
 import (
 	"context"
-	"errors"
+	"fmt"
+	"maps"
+	"slices"
 	"sync"
 	"time"
 
 	"github.com/Aditya-Chowdhary/micro-movies/pkg/discovery"
 )
 
+// Registry is an in-memory service registry mirroring the health semantics of
+// the Consul registry: instances are healthy for a TTL after registering or
+// reporting a healthy state, and a background reaper deregisters instances
+// that stay unhealthy.
 type Registry struct {
 	sync.RWMutex
 	serviceAddrs map[string]map[string]*serviceInstance
+	watchers     map[string]map[chan struct{}]struct{}
+	events       []Event
+
+	opts   Options
+	now    func() time.Time
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// Options configures a registry.
+type Options struct {
+	// HealthTTL is how long an instance stays healthy after registering or
+	// reporting a healthy state. Defaults to discovery.DefaultTTL, the TTL of
+	// Consul health checks.
+	HealthTTL time.Duration
+	// DeregisterAfter is how long an instance may stay unhealthy before it is
+	// deregistered. Zero keeps unhealthy instances registered.
+	DeregisterAfter time.Duration
+	// ReapInterval is how often health transitions are detected and unhealthy
+	// instances deregistered. Defaults to 1s.
+	ReapInterval time.Duration
+}
+
+// DefaultOptions are the options of registries created with NewRegistry.
+var DefaultOptions = Options{
+	HealthTTL:       discovery.DefaultTTL,
+	DeregisterAfter: time.Minute,
+	ReapInterval:    time.Second,
+}
+
+// maxEvents is the number of most recent events kept.
+const maxEvents = 1000
+
+// State is the state of an instance after a transition.
+type State string
+
+const (
+	StateRegistered   State = "registered"
+	StatePassing      State = "passing"
+	StateCritical     State = "critical"
+	StateDeregistered State = "deregistered"
+	// StateReaped means the instance was deregistered after staying critical.
+	StateReaped State = "reaped"
+)
+
+// Event is a state transition of an instance.
+type Event struct {
+	Time        time.Time
+	ServiceName string
+	InstanceID  string
+	State       State
 }
 
 type serviceInstance struct {
 	hostport   string
+	metadata   map[string]string
 	lastActive time.Time
+	passing    bool
 }
 
+// NewRegistry creates a new in-memory registry with DefaultOptions.
 func NewRegistry() *Registry {
-	return &Registry{
+	return NewRegistryWithOptions(DefaultOptions)
+}
+
+// NewRegistryWithOptions creates a new in-memory registry and starts its
+// reaper. Close stops the reaper.
+func NewRegistryWithOptions(opts Options) *Registry {
+	if opts.HealthTTL <= 0 {
+		opts.HealthTTL = DefaultOptions.HealthTTL
+	}
+	if opts.ReapInterval <= 0 {
+		opts.ReapInterval = DefaultOptions.ReapInterval
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	r := &Registry{
 		serviceAddrs: map[string]map[string]*serviceInstance{},
+		watchers:     map[string]map[chan struct{}]struct{}{},
+		opts:         opts,
+		now:          time.Now,
+		cancel:       cancel,
+		done:         make(chan struct{}),
+	}
+	go r.run(ctx)
+	return r
+}
+
+// Close stops the reaper.
+func (r *Registry) Close() {
+	r.cancel()
+	<-r.done
+}
+
+func (r *Registry) run(ctx context.Context) {
+	defer close(r.done)
+	ticker := time.NewTicker(r.opts.ReapInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.reap()
+		}
+	}
+}
+
+// reap records instances going critical and deregisters the ones critical
+// for longer than DeregisterAfter.
+func (r *Registry) reap() {
+	r.Lock()
+	defer r.Unlock()
+	now := r.now()
+	for serviceName, instances := range r.serviceAddrs {
+		changed := false
+		for id, v := range instances {
+			if v.passing && !r.healthyLocked(v, now) {
+				v.passing = false
+				r.recordLocked(serviceName, id, StateCritical)
+				changed = true
+			}
+			if !v.passing && r.opts.DeregisterAfter > 0 && now.Sub(v.lastActive) >= r.opts.HealthTTL+r.opts.DeregisterAfter {
+				delete(instances, id)
+				r.recordLocked(serviceName, id, StateReaped)
+				changed = true
+			}
+		}
+		if changed {
+			r.notifyLocked(serviceName)
+		}
 	}
 }
 
 // Register creates a service record in the registry
-func (r *Registry) Register(ctx context.Context, instanceID string, serviceName string, hostPort string) error {
+func (r *Registry) Register(ctx context.Context, instanceID string, serviceName string, hostPort string, metadata map[string]string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 	r.Lock()
 	defer r.Unlock()
 
 	if _, ok := r.serviceAddrs[serviceName]; !ok {
 		r.serviceAddrs[serviceName] = map[string]*serviceInstance{}
 	}
-	r.serviceAddrs[serviceName][instanceID] = &serviceInstance{hostport: hostPort, lastActive: time.Now()}
+	r.serviceAddrs[serviceName][instanceID] = &serviceInstance{hostport: hostPort, metadata: maps.Clone(metadata), lastActive: r.now(), passing: true}
+	r.recordLocked(serviceName, instanceID, StateRegistered)
+	r.notifyLocked(serviceName)
 	return nil
 }
 
-// Deregister removes a service record in the registry
+// Deregister removes a service record in the registry. Deregistering an
+// unknown instance is not an error.
 func (r *Registry) Deregister(ctx context.Context, instanceID string, serviceName string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 	r.Lock()
 	defer r.Unlock()
-	if _, ok := r.serviceAddrs[serviceName]; !ok {
+	if _, ok := r.serviceAddrs[serviceName][instanceID]; !ok {
 		return nil
 	}
 	delete(r.serviceAddrs[serviceName], instanceID)
+	r.recordLocked(serviceName, instanceID, StateDeregistered)
+	r.notifyLocked(serviceName)
 	return nil
 }
 
+// ReportHealthyState marks an instance healthy for another HealthTTL. It
+// returns an error wrapping discovery.ErrNotFound for unknown instances.
 func (r *Registry) ReportHealthyState(instanceID string, serviceName string) error {
 	r.Lock()
 	defer r.Unlock()
 
-	if _, ok := r.serviceAddrs[serviceName]; !ok {
-		return errors.New("service is not registered yet")
+	v, ok := r.serviceAddrs[serviceName][instanceID]
+	if !ok {
+		return fmt.Errorf("instance %s of %s is not registered: %w", instanceID, serviceName, discovery.ErrNotFound)
 	}
-	if _, ok := r.serviceAddrs[serviceName][instanceID]; !ok {
-		return errors.New("service instance is not registered yet")
+	v.lastActive = r.now()
+	if !v.passing {
+		v.passing = true
+		r.recordLocked(serviceName, instanceID, StatePassing)
+		r.notifyLocked(serviceName)
 	}
-	r.serviceAddrs[serviceName][instanceID].lastActive = time.Now()
 	return nil
 }
 
+// ServiceAddresses returns the list of addresses of active instances of given
+// service, or discovery.ErrNotFound if there are none.
 func (r *Registry) ServiceAddresses(ctx context.Context, serviceName string) ([]string, error) {
+	instances, err := r.ServiceInstances(ctx, serviceName, nil)
+	if err != nil {
+		return nil, err
+	}
+	return discovery.Addresses(instances), nil
+}
+
+// ServiceInstances returns the active instances of given service matching filter.
+func (r *Registry) ServiceInstances(ctx context.Context, serviceName string, filter map[string]string) ([]discovery.Instance, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 	r.RLock()
 	defer r.RUnlock()
-	if len(r.serviceAddrs[serviceName]) == 0 {
+	res := discovery.Filter(r.activeInstancesLocked(serviceName), filter)
+	if len(res) == 0 {
 		return nil, discovery.ErrNotFound
 	}
-	var res []string
-	for _, v := range r.serviceAddrs[serviceName] {
-		if v.lastActive.Before(time.Now().Add(-5 * time.Second)) {
+	return res, nil
+}
+
+// Events returns the recorded instance state transitions, oldest first.
+func (r *Registry) Events() []Event {
+	r.RLock()
+	defer r.RUnlock()
+	return slices.Clone(r.events)
+}
+
+func (r *Registry) activeInstancesLocked(serviceName string) []discovery.Instance {
+	now := r.now()
+	var res []discovery.Instance
+	for id, v := range r.serviceAddrs[serviceName] {
+		if !r.healthyLocked(v, now) {
 			continue
 		}
-		res = append(res, v.hostport)
+		res = append(res, discovery.Instance{ID: id, HostPort: v.hostport, Metadata: maps.Clone(v.metadata)})
+	}
+	discovery.SortInstances(res)
+	return res
+}
+
+func (r *Registry) healthyLocked(v *serviceInstance, now time.Time) bool {
+	return now.Sub(v.lastActive) < r.opts.HealthTTL
+}
+
+func (r *Registry) recordLocked(serviceName string, instanceID string, state State) {
+	r.events = append(r.events, Event{Time: r.now(), ServiceName: serviceName, InstanceID: instanceID, State: state})
+	if len(r.events) > maxEvents {
+		r.events = slices.Delete(r.events, 0, len(r.events)-maxEvents)
+	}
+}
+
+// Watch delivers the active instances of serviceName whenever they change.
+// Instances going critical are noticed by the reaper within ReapInterval.
+func (r *Registry) Watch(ctx context.Context, serviceName string) (<-chan []discovery.Instance, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	notify := make(chan struct{}, 1)
+	r.Lock()
+	if r.watchers[serviceName] == nil {
+		r.watchers[serviceName] = map[chan struct{}]struct{}{}
+	}
+	r.watchers[serviceName][notify] = struct{}{}
+	r.Unlock()
+
+	ch := make(chan []discovery.Instance)
+	go func() {
+		defer close(ch)
+		defer func() {
+			r.Lock()
+			delete(r.watchers[serviceName], notify)
+			r.Unlock()
+		}()
+
+		var last []discovery.Instance
+		first := true
+		for {
+			r.RLock()
+			instances := r.activeInstancesLocked(serviceName)
+			r.RUnlock()
+			if first || !slices.EqualFunc(instances, last, discovery.Instance.Equal) {
+				select {
+				case ch <- instances:
+				case <-ctx.Done():
+					return
+				}
+				last, first = instances, false
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-notify:
+			}
+		}
+	}()
+	return ch, nil
+}
+
+// notifyLocked wakes up the watchers of serviceName. r must be locked.
+func (r *Registry) notifyLocked(serviceName string) {
+	for notify := range r.watchers[serviceName] {
+		select {
+		case notify <- struct{}{}:
+		default:
+		}
 	}
-	return res, nil
 }
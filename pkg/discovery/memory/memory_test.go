@@ -0,0 +1,123 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Aditya-Chowdhary/micro-movies/pkg/discovery"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestRegistry returns a registry with a manual clock whose reaper only runs when called.
+func newTestRegistry(t *testing.T) (*Registry, *time.Time) {
+	r := NewRegistryWithOptions(Options{HealthTTL: 5 * time.Second, DeregisterAfter: time.Minute, ReapInterval: time.Hour})
+	t.Cleanup(r.Close)
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	r.now = func() time.Time { return now }
+	return r, &now
+}
+
+func TestWatch(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	r, now := newTestRegistry(t)
+	require.NoError(t, r.Register(ctx, "a", "svc", "localhost:1", nil))
+
+	ch, err := r.Watch(ctx, "svc")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"localhost:1"}, discovery.Addresses(<-ch))
+
+	require.NoError(t, r.Register(ctx, "b", "svc", "localhost:2", nil))
+	assert.Equal(t, []string{"localhost:1", "localhost:2"}, discovery.Addresses(<-ch))
+
+	require.NoError(t, r.ReportHealthyState("a", "svc"))
+	require.NoError(t, r.Deregister(ctx, "a", "svc"))
+	assert.Equal(t, []string{"localhost:2"}, discovery.Addresses(<-ch), "unchanged sets are not delivered")
+
+	// Instances that stop reporting their health go critical.
+	*now = now.Add(5 * time.Second)
+	r.reap()
+	assert.Empty(t, <-ch)
+
+	cancel()
+	_, ok := <-ch
+	assert.False(t, ok, "channel is closed when the context is done")
+	r.RLock()
+	defer r.RUnlock()
+	assert.Empty(t, r.watchers["svc"])
+}
+
+func TestReaper(t *testing.T) {
+	ctx := context.Background()
+	r, now := newTestRegistry(t)
+	require.NoError(t, r.Register(ctx, "a", "svc", "localhost:1", nil))
+
+	*now = now.Add(5 * time.Second)
+	_, err := r.ServiceAddresses(ctx, "svc")
+	assert.ErrorIs(t, err, discovery.ErrNotFound, "critical instances are not served")
+	r.reap()
+	require.NoError(t, r.ReportHealthyState("a", "svc"))
+	addrs, err := r.ServiceAddresses(ctx, "svc")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"localhost:1"}, addrs)
+
+	*now = now.Add(5 * time.Second)
+	r.reap()
+	*now = now.Add(time.Minute - time.Second)
+	r.reap()
+	require.NoError(t, r.ReportHealthyState("a", "svc"), "instances are kept for DeregisterAfter")
+
+	*now = now.Add(time.Minute + 5*time.Second)
+	r.reap()
+	r.reap()
+	assert.ErrorIs(t, r.ReportHealthyState("a", "svc"), discovery.ErrNotFound)
+	require.NoError(t, r.Deregister(ctx, "a", "svc"), "deregistering an unknown instance is not an error")
+
+	var states []State
+	for _, e := range r.Events() {
+		assert.Equal(t, "svc", e.ServiceName)
+		assert.Equal(t, "a", e.InstanceID)
+		states = append(states, e.State)
+	}
+	assert.Equal(t, []State{
+		StateRegistered,
+		StateCritical, StatePassing,
+		StateCritical, StatePassing,
+		StateCritical, StateReaped,
+	}, states)
+}
+
+func TestCancelledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	r, _ := newTestRegistry(t)
+	assert.ErrorIs(t, r.Register(ctx, "a", "svc", "localhost:1", nil), context.Canceled)
+	assert.ErrorIs(t, r.Deregister(ctx, "a", "svc"), context.Canceled)
+	_, err := r.ServiceAddresses(ctx, "svc")
+	assert.ErrorIs(t, err, context.Canceled)
+	_, err = r.Watch(ctx, "svc")
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestServiceInstances(t *testing.T) {
+	ctx := context.Background()
+	r, _ := newTestRegistry(t)
+	_, err := r.ServiceInstances(ctx, "svc", nil)
+	assert.ErrorIs(t, err, discovery.ErrNotFound)
+
+	require.NoError(t, r.Register(ctx, "a", "svc", "localhost:1", map[string]string{"version": "v1", "zone": "a"}))
+	require.NoError(t, r.Register(ctx, "b", "svc", "localhost:2", map[string]string{"version": "v2", "zone": "a"}))
+
+	all, err := r.ServiceInstances(ctx, "svc", nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"localhost:1", "localhost:2"}, discovery.Addresses(all))
+
+	v2, err := r.ServiceInstances(ctx, "svc", map[string]string{"version": "v2", "zone": "a"})
+	require.NoError(t, err)
+	assert.Equal(t, []discovery.Instance{{ID: "b", HostPort: "localhost:2", Metadata: map[string]string{"version": "v2", "zone": "a"}}}, v2)
+
+	_, err = r.ServiceInstances(ctx, "svc", map[string]string{"version": "v3"})
+	assert.ErrorIs(t, err, discovery.ErrNotFound)
+}
@@ -0,0 +1,87 @@
+// Package setup creates the service registry selected by a service's configuration.
+package setup
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Aditya-Chowdhary/micro-movies/pkg/discovery"
+	"github.com/Aditya-Chowdhary/micro-movies/pkg/discovery/consul"
+	"github.com/Aditya-Chowdhary/micro-movies/pkg/discovery/dns"
+	"github.com/Aditya-Chowdhary/micro-movies/pkg/discovery/file"
+	"github.com/Aditya-Chowdhary/micro-movies/pkg/discovery/memory"
+)
+
+// DefaultConsulAddress is the address of the local Consul agent.
+const DefaultConsulAddress = "localhost:8500"
+
+// Config selects and configures a service registry.
+type Config struct {
+	// Type selects the registry: consul, memory, file or dns. Defaults to consul.
+	Type   string       `yaml:"type"`
+	Consul ConsulConfig `yaml:"consul"`
+	File   FileConfig   `yaml:"file"`
+	DNS    DNSConfig    `yaml:"dns"`
+}
+
+// ConsulConfig configures the Consul registry.
+type ConsulConfig struct {
+	// Address is the address of the Consul agent.
+	Address string `yaml:"address"`
+}
+
+// FileConfig configures the file registry.
+type FileConfig struct {
+	// Path is the YAML or JSON file listing the instances of each service.
+	Path string `yaml:"path"`
+	// ReloadInterval is how often the file is checked for changes.
+	ReloadInterval time.Duration `yaml:"reloadInterval"`
+}
+
+// DNSConfig configures the DNS SRV registry.
+type DNSConfig struct {
+	// Domain is the domain of the _<service>._<proto>.<domain> SRV records.
+	Domain string `yaml:"domain"`
+	// Proto is the protocol of the SRV records. Defaults to tcp.
+	Proto string `yaml:"proto"`
+	// RefreshInterval is how often watched services are looked up again.
+	RefreshInterval time.Duration `yaml:"refreshInterval"`
+}
+
+// NewRegistry creates the registry selected by cfg. The returned function
+// releases it and must be called once the registry is no longer used.
+func NewRegistry(cfg Config) (discovery.Registry, func(), error) {
+	switch cfg.Type {
+	case "", "consul":
+		addr := cfg.Consul.Address
+		if addr == "" {
+			addr = DefaultConsulAddress
+		}
+		r, err := consul.NewRegistry(addr)
+		if err != nil {
+			return nil, nil, err
+		}
+		return r, func() {}, nil
+	case "memory":
+		r := memory.NewRegistry()
+		return r, r.Close, nil
+	case "file":
+		r, err := file.NewRegistry(cfg.File.Path, cfg.File.ReloadInterval)
+		if err != nil {
+			return nil, nil, err
+		}
+		return r, r.Close, nil
+	case "dns":
+		r, err := dns.NewRegistry(dns.Options{
+			Domain:          cfg.DNS.Domain,
+			Proto:           cfg.DNS.Proto,
+			RefreshInterval: cfg.DNS.RefreshInterval,
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		return r, func() {}, nil
+	default:
+		return nil, nil, fmt.Errorf("unknown registry type %q", cfg.Type)
+	}
+}
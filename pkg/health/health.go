@@ -0,0 +1,212 @@
+// Package health computes the readiness of a service from checks of its
+// dependencies and serves it over the gRPC health checking protocol.
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Aditya-Chowdhary/micro-movies/pkg/discovery"
+
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// Defaults of Options.
+const (
+	DefaultInterval = time.Second
+	DefaultTimeout  = time.Second
+)
+
+// Check returns an error if a dependency of the service is unavailable.
+type Check func(ctx context.Context) error
+
+// Options configures a Checker.
+type Options struct {
+	// Interval is how often the checks run. Defaults to DefaultInterval.
+	Interval time.Duration
+	// Timeout bounds each run of a check. Defaults to DefaultTimeout.
+	Timeout time.Duration
+}
+
+type namedCheck struct {
+	name     string
+	check    Check
+	optional bool
+}
+
+// Checker periodically runs dependency checks and reports the service as
+// serving on its health server while they all pass. Failed checks of optional
+// dependencies only mark the service degraded.
+type Checker struct {
+	server   *grpchealth.Server
+	services []string
+	opts     Options
+
+	mu       sync.Mutex
+	checks   []namedCheck
+	ready    bool
+	degraded error
+
+	// OnChange is called with the error of the failed checks when the service
+	// becomes unready and with nil when it becomes ready. It may be nil.
+	OnChange func(err error)
+	// OnDegradedChange is called with the error of the failed optional checks
+	// when the service becomes degraded and with nil when it recovers. It may
+	// be nil.
+	OnDegradedChange func(err error)
+}
+
+// NewChecker creates a checker reporting the status of services, and of the
+// server as a whole, on server. The service is unready until the checks pass.
+func NewChecker(server *grpchealth.Server, opts Options, services ...string) *Checker {
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultInterval
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultTimeout
+	}
+	c := &Checker{
+		server:   server,
+		services: append([]string{""}, services...),
+		opts:     opts,
+	}
+	c.setStatus(healthpb.HealthCheckResponse_NOT_SERVING)
+	return c
+}
+
+// Add adds a check of the named dependency.
+func (c *Checker) Add(name string, check Check) {
+	c.add(namedCheck{name: name, check: check})
+}
+
+// AddOptional adds a check of the named dependency the service can serve
+// without, e.g. by degrading its responses. Its failures are reported by
+// Degraded and leave the service ready.
+func (c *Checker) AddOptional(name string, check Check) {
+	c.add(namedCheck{name: name, check: check, optional: true})
+}
+
+func (c *Checker) add(nc namedCheck) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.checks = append(c.checks, nc)
+}
+
+// Ready reports whether all checks passed the last time they ran.
+func (c *Checker) Ready() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ready
+}
+
+// Degraded returns the error of the optional checks that failed the last time
+// they ran, or nil if they all passed.
+func (c *Checker) Degraded() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.degraded
+}
+
+// Update runs the checks, updates the readiness of the service and returns
+// the error of the failed required checks.
+func (c *Checker) Update(ctx context.Context) error {
+	c.mu.Lock()
+	checks := c.checks
+	c.mu.Unlock()
+
+	errs := make([]error, len(checks))
+	optionalErrs := make([]error, len(checks))
+	var wg sync.WaitGroup
+	for i, nc := range checks {
+		wg.Add(1)
+		go func(i int, nc namedCheck) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(ctx, c.opts.Timeout)
+			defer cancel()
+			err := nc.check(ctx)
+			if err == nil {
+				return
+			}
+			err = fmt.Errorf("%s: %w", nc.name, err)
+			if nc.optional {
+				optionalErrs[i] = err
+			} else {
+				errs[i] = err
+			}
+		}(i, nc)
+	}
+	wg.Wait()
+	err := errors.Join(errs...)
+	degraded := errors.Join(optionalErrs...)
+
+	c.mu.Lock()
+	changed := c.ready != (err == nil)
+	c.ready = err == nil
+	degradedChanged := (c.degraded == nil) != (degraded == nil)
+	c.degraded = degraded
+	c.mu.Unlock()
+
+	if degradedChanged && c.OnDegradedChange != nil {
+		c.OnDegradedChange(degraded)
+	}
+
+	if changed {
+		if err == nil {
+			c.setStatus(healthpb.HealthCheckResponse_SERVING)
+		} else {
+			c.setStatus(healthpb.HealthCheckResponse_NOT_SERVING)
+		}
+		if c.OnChange != nil {
+			c.OnChange(err)
+		}
+	}
+	return err
+}
+
+// Run runs the checks every Interval until ctx is done, then reports the
+// service as not serving.
+func (c *Checker) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.opts.Interval)
+	defer ticker.Stop()
+	for {
+		c.Update(ctx)
+		select {
+		case <-ctx.Done():
+			c.mu.Lock()
+			c.ready = false
+			c.mu.Unlock()
+			c.server.Shutdown()
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (c *Checker) setStatus(status healthpb.HealthCheckResponse_ServingStatus) {
+	for _, service := range c.services {
+		c.server.SetServingStatus(service, status)
+	}
+}
+
+// Pinger is a dependency that can be pinged, like *sql.DB.
+type Pinger interface {
+	PingContext(ctx context.Context) error
+}
+
+// Ping checks that p responds to pings.
+func Ping(p Pinger) Check {
+	return p.PingContext
+}
+
+// Instances checks that registry has active instances of serviceName
+// matching filter.
+func Instances(registry discovery.Registry, serviceName string, filter map[string]string) Check {
+	return func(ctx context.Context) error {
+		_, err := registry.ServiceInstances(ctx, serviceName, filter)
+		return err
+	}
+}
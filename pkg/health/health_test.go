@@ -0,0 +1,129 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Aditya-Chowdhary/micro-movies/pkg/discovery"
+	"github.com/Aditya-Chowdhary/micro-movies/pkg/discovery/memory"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func status(t *testing.T, server *grpchealth.Server, service string) healthpb.HealthCheckResponse_ServingStatus {
+	t.Helper()
+	res, err := server.Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+	require.NoError(t, err)
+	return res.Status
+}
+
+func TestChecker(t *testing.T) {
+	server := grpchealth.NewServer()
+	c := NewChecker(server, Options{}, "TestService")
+	var failing atomic.Bool
+	c.Add("dependency", func(ctx context.Context) error {
+		if failing.Load() {
+			return errors.New("unavailable")
+		}
+		return nil
+	})
+	var changes []error
+	c.OnChange = func(err error) { changes = append(changes, err) }
+
+	assert.False(t, c.Ready(), "unready before the checks ran")
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, status(t, server, ""))
+
+	require.NoError(t, c.Update(context.Background()))
+	assert.True(t, c.Ready())
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, status(t, server, ""))
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, status(t, server, "TestService"))
+
+	failing.Store(true)
+	err := c.Update(context.Background())
+	assert.ErrorContains(t, err, "dependency: unavailable")
+	assert.False(t, c.Ready())
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, status(t, server, "TestService"))
+
+	// Unchanged readiness is not reported again.
+	c.Update(context.Background())
+	require.Len(t, changes, 2)
+	assert.NoError(t, changes[0])
+	assert.Error(t, changes[1])
+}
+
+func TestCheckerOptional(t *testing.T) {
+	c := NewChecker(grpchealth.NewServer(), Options{})
+	var failing atomic.Bool
+	c.Add("required", func(ctx context.Context) error { return nil })
+	c.AddOptional("optional", func(ctx context.Context) error {
+		if failing.Load() {
+			return errors.New("unavailable")
+		}
+		return nil
+	})
+	var changes []error
+	c.OnDegradedChange = func(err error) { changes = append(changes, err) }
+
+	require.NoError(t, c.Update(context.Background()))
+	assert.True(t, c.Ready())
+	assert.NoError(t, c.Degraded())
+
+	failing.Store(true)
+	require.NoError(t, c.Update(context.Background()), "optional failures leave the service ready")
+	assert.True(t, c.Ready())
+	assert.ErrorContains(t, c.Degraded(), "optional: unavailable")
+
+	c.Update(context.Background())
+	failing.Store(false)
+	c.Update(context.Background())
+	assert.NoError(t, c.Degraded())
+	require.Len(t, changes, 2, "only changes of degradation are reported")
+	assert.Error(t, changes[0])
+	assert.NoError(t, changes[1])
+}
+
+func TestCheckerTimeout(t *testing.T) {
+	c := NewChecker(grpchealth.NewServer(), Options{Timeout: 10 * time.Millisecond})
+	c.Add("slow", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	assert.ErrorIs(t, c.Update(context.Background()), context.DeadlineExceeded)
+	assert.False(t, c.Ready())
+}
+
+func TestCheckerRun(t *testing.T) {
+	server := grpchealth.NewServer()
+	c := NewChecker(server, Options{Interval: 10 * time.Millisecond})
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		c.Run(ctx)
+	}()
+	require.Eventually(t, c.Ready, time.Second, 5*time.Millisecond)
+
+	cancel()
+	<-done
+	assert.False(t, c.Ready())
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, status(t, server, ""))
+}
+
+func TestInstances(t *testing.T) {
+	registry := memory.NewRegistry()
+	defer registry.Close()
+	check := Instances(registry, "rating", map[string]string{discovery.MetadataVersion: "v2"})
+
+	ctx := context.Background()
+	require.NoError(t, registry.Register(ctx, "rating-1", "rating", "localhost:1", map[string]string{discovery.MetadataVersion: "v1"}))
+	assert.ErrorIs(t, check(ctx), discovery.ErrNotFound)
+
+	require.NoError(t, registry.Register(ctx, "rating-2", "rating", "localhost:2", map[string]string{discovery.MetadataVersion: "v2"}))
+	assert.NoError(t, check(ctx))
+}
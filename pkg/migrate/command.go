@@ -0,0 +1,52 @@
+package migrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// Usage describes the arguments of the migrate subcommand.
+const Usage = "usage: migrate up | down [steps] | status"
+
+// RunCommand runs the migrate subcommand described by args with m, writing
+// its results to w.
+func RunCommand(ctx context.Context, m *Migrator, args []string, w io.Writer) error {
+	if len(args) == 0 {
+		return errors.New(Usage)
+	}
+	switch args[0] {
+	case "up":
+		n, err := m.Up(ctx)
+		fmt.Fprintf(w, "applied %d migrations\n", n)
+		return err
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			var err error
+			if steps, err = strconv.Atoi(args[1]); err != nil || steps < 1 {
+				return fmt.Errorf("invalid steps %q", args[1])
+			}
+		}
+		n, err := m.Down(ctx, steps)
+		fmt.Fprintf(w, "reverted %d migrations\n", n)
+		return err
+	case "status":
+		statuses, err := m.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, s := range statuses {
+			applied := "pending"
+			if s.AppliedAt != nil {
+				applied = "applied " + s.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Fprintf(w, "%04d_%s\t%s\n", s.Version, s.Name, applied)
+		}
+		return nil
+	default:
+		return errors.New(Usage)
+	}
+}
@@ -0,0 +1,38 @@
+package migrate
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunCommand(t *testing.T) {
+	ctx := context.Background()
+	m := New(openSQLite(t, filepath.Join(t.TempDir(), "test.db")), SQLite, "schema_migrations", testMigrations)
+	run := func(args ...string) (string, error) {
+		var out bytes.Buffer
+		err := RunCommand(ctx, m, args, &out)
+		return out.String(), err
+	}
+
+	out, err := run("up")
+	require.NoError(t, err)
+	assert.Equal(t, "applied 2 migrations\n", out)
+
+	out, err = run("down", "2")
+	require.NoError(t, err)
+	assert.Equal(t, "reverted 2 migrations\n", out)
+
+	out, err = run("status")
+	require.NoError(t, err)
+	assert.Equal(t, "0001_create_a\tpending\n0002_create_b\tpending\n", out)
+
+	for _, args := range [][]string{nil, {"sideways"}, {"down", "0"}, {"down", "x"}} {
+		_, err := run(args...)
+		assert.Error(t, err, "%v", args)
+	}
+}
@@ -0,0 +1,365 @@
+package migrate
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrChecksumMismatch is returned when an applied migration no longer matches its source.
+var ErrChecksumMismatch = errors.New("migration checksum mismatch")
+
+// ErrUnknownVersion is returned when the database is at a version with no known migration.
+var ErrUnknownVersion = errors.New("unknown migration version")
+
+var fileRe = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// Migration is a single versioned schema change.
+type Migration struct {
+	Version  int64
+	Name     string
+	Up       string
+	Down     string
+	Checksum string
+}
+
+// Status describes a migration and whether it has been applied.
+type Status struct {
+	Migration
+	AppliedAt *time.Time
+}
+
+// Load reads migrations from files named <version>_<name>.up.sql and
+// <version>_<name>.down.sql in the root of fsys, ordered by version.
+func Load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+	byVersion := map[int64]*Migration{}
+	for _, e := range entries {
+		match := fileRe.FindStringSubmatch(e.Name())
+		if match == nil {
+			continue
+		}
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		} else if m.Name != match[2] {
+			return nil, fmt.Errorf("migration %d has conflicting names %q and %q", version, m.Name, match[2])
+		}
+		b, err := fs.ReadFile(fsys, e.Name())
+		if err != nil {
+			return nil, err
+		}
+		if match[3] == "up" {
+			m.Up = string(b)
+		} else {
+			m.Down = string(b)
+		}
+	}
+	res := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" {
+			return nil, fmt.Errorf("migration %d_%s has no up script", m.Version, m.Name)
+		}
+		sum := sha256.Sum256([]byte(m.Up))
+		m.Checksum = hex.EncodeToString(sum[:])
+		res = append(res, *m)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Version < res[j].Version })
+	return res, nil
+}
+
+// Dialect is the SQL dialect of a migrated database.
+type Dialect int
+
+const (
+	// MySQL commits schema changes implicitly, so a migration and its record
+	// cannot be applied atomically. Migrators serialize through GET_LOCK.
+	MySQL Dialect = iota
+	// SQLite applies each migration and its record in one transaction.
+	// Migrators serialize through the database write lock.
+	SQLite
+)
+
+// lockTimeout bounds the wait for another migrator to finish.
+const lockTimeout = time.Minute
+
+// Migrator applies migrations to a SQL database and records them in a
+// migration table. Migrators of the same table, e.g. in several instances of a
+// service starting together, run one at a time.
+type Migrator struct {
+	db         *sql.DB
+	dialect    Dialect
+	table      string
+	migrations []Migration
+}
+
+// New creates a new migrator recording applied migrations in table.
+// Services sharing a database must use distinct tables.
+func New(db *sql.DB, dialect Dialect, table string, migrations []Migration) *Migrator {
+	return &Migrator{db, dialect, table, migrations}
+}
+
+// Up applies all pending migrations in order and returns how many were applied.
+// It refuses to run if an applied migration has been modified since.
+func (m *Migrator) Up(ctx context.Context) (int, error) {
+	n := 0
+	err := m.session(ctx, func(conn *sql.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for _, mig := range m.migrations {
+			if _, ok := applied[mig.Version]; ok {
+				continue
+			}
+			query := fmt.Sprintf("INSERT INTO %s (version, name, checksum, applied_at) VALUES (?, ?, ?, ?)", m.table)
+			if err := m.step(ctx, conn, mig.Up, query, mig.Version, mig.Name, mig.Checksum, m.dialect.timestamp(time.Now().UTC())); err != nil {
+				return fmt.Errorf("apply migration %d_%s: %w", mig.Version, mig.Name, err)
+			}
+			n++
+		}
+		return nil
+	})
+	return n, err
+}
+
+// Down reverts the last steps applied migrations, newest first, and returns
+// how many were reverted.
+func (m *Migrator) Down(ctx context.Context, steps int) (int, error) {
+	n := 0
+	err := m.session(ctx, func(conn *sql.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for i := len(m.migrations) - 1; i >= 0 && n < steps; i-- {
+			mig := m.migrations[i]
+			if _, ok := applied[mig.Version]; !ok {
+				continue
+			}
+			if mig.Down == "" {
+				return fmt.Errorf("migration %d_%s has no down script", mig.Version, mig.Name)
+			}
+			query := fmt.Sprintf("DELETE FROM %s WHERE version = ?", m.table)
+			if err := m.step(ctx, conn, mig.Down, query, mig.Version); err != nil {
+				return fmt.Errorf("revert migration %d_%s: %w", mig.Version, mig.Name, err)
+			}
+			n++
+		}
+		return nil
+	})
+	return n, err
+}
+
+// Status lists all known migrations with the time each was applied, if it was.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	var res []Status
+	err := m.session(ctx, func(conn *sql.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		res = make([]Status, 0, len(m.migrations))
+		for _, mig := range m.migrations {
+			s := Status{Migration: mig}
+			if a, ok := applied[mig.Version]; ok {
+				s.AppliedAt = &a.at
+			}
+			res = append(res, s)
+		}
+		return nil
+	})
+	return res, err
+}
+
+// session runs fn on a dedicated connection while holding the migration lock.
+func (m *Migrator) session(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	switch m.dialect {
+	case SQLite:
+		// An immediate transaction takes the write lock, waiting for other
+		// writers up to the busy timeout. Migrations applied before a failure
+		// are committed; the failed one was rolled back by step.
+		if _, err := conn.ExecContext(ctx, "BEGIN IMMEDIATE"); err != nil {
+			return fmt.Errorf("lock migrations: %w", err)
+		}
+		err := fn(conn)
+		if _, cerr := conn.ExecContext(context.WithoutCancel(ctx), "COMMIT"); cerr != nil && err == nil {
+			err = cerr
+		}
+		return err
+	default:
+		var locked sql.NullInt64
+		if err := conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, ?)", m.table, int(lockTimeout.Seconds())).Scan(&locked); err != nil {
+			return fmt.Errorf("lock migrations: %w", err)
+		}
+		if locked.Int64 != 1 {
+			return fmt.Errorf("lock migrations: timed out after %s waiting for another migrator", lockTimeout)
+		}
+		defer conn.ExecContext(context.WithoutCancel(ctx), "SELECT RELEASE_LOCK(?)", m.table)
+		return fn(conn)
+	}
+}
+
+// step runs a migration script followed by the query recording it, both in
+// one savepoint where the dialect has transactional schema changes.
+func (m *Migrator) step(ctx context.Context, conn *sql.Conn, script string, record string, args ...any) error {
+	if m.dialect != SQLite {
+		if err := exec(ctx, conn, script); err != nil {
+			return err
+		}
+		_, err := conn.ExecContext(ctx, record, args...)
+		return err
+	}
+
+	if _, err := conn.ExecContext(ctx, "SAVEPOINT migration"); err != nil {
+		return err
+	}
+	err := exec(ctx, conn, script)
+	if err == nil {
+		_, err = conn.ExecContext(ctx, record, args...)
+	}
+	if err != nil {
+		conn.ExecContext(context.WithoutCancel(ctx), "ROLLBACK TO migration")
+	}
+	if _, rerr := conn.ExecContext(context.WithoutCancel(ctx), "RELEASE migration"); rerr != nil && err == nil {
+		err = rerr
+	}
+	return err
+}
+
+type appliedMigration struct {
+	checksum string
+	at       time.Time
+}
+
+// timestampType is the column type of applied_at. SQLite has no date type, so
+// times are stored as unix nanoseconds like in the SQLite repositories.
+func (d Dialect) timestampType() string {
+	if d == SQLite {
+		return "INTEGER"
+	}
+	return "DATETIME(6)"
+}
+
+// timestamp returns the applied_at value of t.
+func (d Dialect) timestamp(t time.Time) any {
+	if d == SQLite {
+		return t.UnixNano()
+	}
+	return t
+}
+
+// scanTimestamp returns a scan destination for applied_at and a function
+// returning the scanned time.
+func (d Dialect) scanTimestamp() (any, func() time.Time) {
+	if d == SQLite {
+		var v int64
+		return &v, func() time.Time { return time.Unix(0, v).UTC() }
+	}
+	var v time.Time
+	return &v, func() time.Time { return v }
+}
+
+// applied creates the migration table if needed and returns the applied
+// migrations after verifying them against the known ones.
+func (m *Migrator) applied(ctx context.Context, conn *sql.Conn) (map[int64]appliedMigration, error) {
+	query := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	version BIGINT NOT NULL PRIMARY KEY,
+	name VARCHAR(255) NOT NULL,
+	checksum CHAR(64) NOT NULL,
+	applied_at %s NOT NULL
+	)`, m.table, m.dialect.timestampType())
+	if _, err := conn.ExecContext(ctx, query); err != nil {
+		return nil, err
+	}
+	rows, err := conn.QueryContext(ctx, fmt.Sprintf("SELECT version, checksum, applied_at FROM %s", m.table))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := map[int64]appliedMigration{}
+	for rows.Next() {
+		var version int64
+		var a appliedMigration
+		at, scanned := m.dialect.scanTimestamp()
+		if err := rows.Scan(&version, &a.checksum, at); err != nil {
+			return nil, err
+		}
+		a.at = scanned()
+		res[version] = a
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	known := make(map[int64]Migration, len(m.migrations))
+	for _, mig := range m.migrations {
+		known[mig.Version] = mig
+	}
+	for version, a := range res {
+		mig, ok := known[version]
+		if !ok {
+			return nil, fmt.Errorf("%w: %d", ErrUnknownVersion, version)
+		}
+		if mig.Checksum != a.checksum {
+			return nil, fmt.Errorf("%w: %d_%s", ErrChecksumMismatch, mig.Version, mig.Name)
+		}
+	}
+	return res, nil
+}
+
+// exec runs each statement of a script in turn.
+func exec(ctx context.Context, conn *sql.Conn, script string) error {
+	for _, stmt := range Split(script) {
+		if _, err := conn.ExecContext(ctx, stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Split splits a script into statements terminated by a semicolon at the end
+// of a line. Lines starting with -- are ignored.
+func Split(script string) []string {
+	var res []string
+	var b strings.Builder
+	for _, line := range strings.Split(script, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+		b.WriteString(line)
+		b.WriteString("\n")
+		if strings.HasSuffix(trimmed, ";") {
+			res = append(res, strings.TrimSuffix(strings.TrimSpace(b.String()), ";"))
+			b.Reset()
+		}
+	}
+	if s := strings.TrimSpace(b.String()); s != "" {
+		res = append(res, s)
+	}
+	return res
+}
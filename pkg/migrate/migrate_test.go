@@ -0,0 +1,152 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"sync"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/Aditya-Chowdhary/micro-movies/pkg/sqldb"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad(t *testing.T) {
+	fsys := fstest.MapFS{
+		"0002_add_keys.up.sql":   {Data: []byte("ALTER TABLE t ADD PRIMARY KEY (id);")},
+		"0002_add_keys.down.sql": {Data: []byte("ALTER TABLE t DROP PRIMARY KEY;")},
+		"0001_create.up.sql":     {Data: []byte("CREATE TABLE t (id INT);")},
+		"README.md":              {Data: []byte("ignored")},
+	}
+	migrations, err := Load(fsys)
+	assert.NoError(t, err)
+	assert.Len(t, migrations, 2)
+	assert.Equal(t, int64(1), migrations[0].Version)
+	assert.Equal(t, "create", migrations[0].Name)
+	assert.Empty(t, migrations[0].Down)
+	assert.Equal(t, "add_keys", migrations[1].Name)
+	assert.Equal(t, "ALTER TABLE t DROP PRIMARY KEY;", migrations[1].Down)
+	assert.Len(t, migrations[1].Checksum, 64)
+
+	_, err = Load(fstest.MapFS{"0001_create.down.sql": {Data: []byte("DROP TABLE t;")}})
+	assert.Error(t, err)
+}
+
+func TestSplit(t *testing.T) {
+	script := `-- comment
+CREATE TABLE a (
+    id INT
+);
+
+DROP TABLE b;
+SELECT 1`
+	assert.Equal(t, []string{"CREATE TABLE a (\n    id INT\n)", "DROP TABLE b", "SELECT 1"}, Split(script))
+}
+
+func openSQLite(t *testing.T, path string) *sql.DB {
+	t.Helper()
+	db, err := sqldb.OpenSQLite(context.Background(), sqldb.SQLiteConfig{Path: path})
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func tableExists(t *testing.T, db *sql.DB, name string) bool {
+	t.Helper()
+	var n int
+	require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?", name).Scan(&n))
+	return n > 0
+}
+
+var testMigrations = []Migration{
+	{Version: 1, Name: "create_a", Up: "CREATE TABLE a (id INTEGER);", Down: "DROP TABLE a;", Checksum: "1"},
+	{Version: 2, Name: "create_b", Up: "CREATE TABLE b (id INTEGER);", Down: "DROP TABLE b;", Checksum: "2"},
+}
+
+func TestMigrator(t *testing.T) {
+	ctx := context.Background()
+	db := openSQLite(t, filepath.Join(t.TempDir(), "test.db"))
+	m := New(db, SQLite, "schema_migrations", testMigrations)
+
+	n, err := m.Up(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+	assert.True(t, tableExists(t, db, "b"))
+
+	n, err = m.Up(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, n, "applied migrations are skipped")
+
+	statuses, err := m.Status(ctx)
+	require.NoError(t, err)
+	require.Len(t, statuses, 2)
+	require.NotNil(t, statuses[1].AppliedAt)
+	assert.WithinDuration(t, time.Now(), *statuses[1].AppliedAt, time.Minute)
+
+	n, err = m.Down(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.False(t, tableExists(t, db, "b"))
+	assert.True(t, tableExists(t, db, "a"))
+}
+
+func TestMigratorFailure(t *testing.T) {
+	ctx := context.Background()
+	db := openSQLite(t, filepath.Join(t.TempDir(), "test.db"))
+	migrations := append(testMigrations[:1:1], Migration{
+		Version: 2, Name: "broken", Up: "CREATE TABLE b (id INTEGER);\nNOT SQL;", Checksum: "2",
+	})
+	m := New(db, SQLite, "schema_migrations", migrations)
+
+	n, err := m.Up(ctx)
+	assert.Error(t, err)
+	assert.Equal(t, 1, n)
+	assert.True(t, tableExists(t, db, "a"), "migrations before the failure are kept")
+	assert.False(t, tableExists(t, db, "b"), "the failed migration is rolled back")
+
+	statuses, err := m.Status(ctx)
+	require.NoError(t, err)
+	assert.NotNil(t, statuses[0].AppliedAt)
+	assert.Nil(t, statuses[1].AppliedAt, "the failed migration is not recorded")
+
+	// Once fixed, the migration applies cleanly.
+	n, err = New(db, SQLite, "schema_migrations", testMigrations).Up(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+}
+
+func TestMigratorConcurrent(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "test.db")
+	const migrators = 4
+
+	var wg sync.WaitGroup
+	applied := make(chan int, migrators)
+	errs := make(chan error, migrators)
+	for i := 0; i < migrators; i++ {
+		// Separate pools stand in for separate service instances.
+		m := New(openSQLite(t, path), SQLite, "schema_migrations", testMigrations)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			n, err := m.Up(ctx)
+			applied <- n
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(applied)
+	close(errs)
+	for err := range errs {
+		require.NoError(t, err)
+	}
+	total := 0
+	for n := range applied {
+		total += n
+	}
+	assert.Equal(t, len(testMigrations), total, "each migration is applied once")
+}
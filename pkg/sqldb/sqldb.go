@@ -0,0 +1,186 @@
+package sqldb
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// Environment variables overriding the credentials in Config.
+const (
+	UserEnv     = "MYSQL_USER"
+	PasswordEnv = "MYSQL_PASSWORD"
+)
+
+const defaultPingTimeout = 30 * time.Second
+
+// Config defines how to connect to a MySQL database.
+type Config struct {
+	// DSN is the data source name without credentials, e.g. tcp(localhost:3306)/movieexample.
+	DSN string `yaml:"dsn"`
+	// User and Password are overridden by the MYSQL_USER and MYSQL_PASSWORD
+	// environment variables. PasswordFile, if set, takes precedence over both.
+	User         string    `yaml:"user"`
+	Password     string    `yaml:"password"`
+	PasswordFile string    `yaml:"passwordFile"`
+	TLS          TLSConfig `yaml:"tls"`
+
+	// Pool settings. Unset or zero settings keep the database/sql defaults.
+	MaxOpenConns    int           `yaml:"maxOpenConns"`
+	MaxIdleConns    int           `yaml:"maxIdleConns"`
+	ConnMaxLifetime time.Duration `yaml:"connMaxLifetime"`
+	ConnMaxIdleTime time.Duration `yaml:"connMaxIdleTime"`
+
+	// PingTimeout bounds how long Open waits for the database to become reachable.
+	PingTimeout time.Duration `yaml:"pingTimeout"`
+}
+
+// TLSConfig defines TLS settings for the database connection.
+type TLSConfig struct {
+	Enabled            bool   `yaml:"enabled"`
+	CAFile             string `yaml:"caFile"`
+	CertFile           string `yaml:"certFile"`
+	KeyFile            string `yaml:"keyFile"`
+	ServerName         string `yaml:"serverName"`
+	InsecureSkipVerify bool   `yaml:"insecureSkipVerify"`
+}
+
+// Open connects to the MySQL database described by cfg, applies the pool
+// settings and waits until the database answers a ping or PingTimeout elapses.
+func Open(ctx context.Context, cfg Config) (*sql.DB, error) {
+	mcfg, err := cfg.mysqlConfig()
+	if err != nil {
+		return nil, err
+	}
+	connector, err := mysql.NewConnector(mcfg)
+	if err != nil {
+		return nil, err
+	}
+	db := sql.OpenDB(connector)
+	cfg.configurePool(db)
+
+	timeout := cfg.PingTimeout
+	if timeout == 0 {
+		timeout = defaultPingTimeout
+	}
+	if err := waitReady(ctx, db, timeout); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+// pool is the connection pool settings of *sql.DB.
+type pool interface {
+	SetMaxOpenConns(n int)
+	SetMaxIdleConns(n int)
+	SetConnMaxLifetime(d time.Duration)
+	SetConnMaxIdleTime(d time.Duration)
+}
+
+// configurePool applies the pool settings of cfg. Unset settings keep the
+// database/sql defaults; in particular, zero would disable idle connections.
+func (cfg Config) configurePool(p pool) {
+	if cfg.MaxOpenConns > 0 {
+		p.SetMaxOpenConns(cfg.MaxOpenConns)
+	}
+	if cfg.MaxIdleConns > 0 {
+		p.SetMaxIdleConns(cfg.MaxIdleConns)
+	}
+	if cfg.ConnMaxLifetime > 0 {
+		p.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	}
+	if cfg.ConnMaxIdleTime > 0 {
+		p.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
+	}
+}
+
+// waitReady pings db until it responds or timeout elapses.
+func waitReady(ctx context.Context, db *sql.DB, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	for {
+		err := db.PingContext(ctx)
+		if err == nil {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("database not ready after %v: %w", timeout, err)
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+func (cfg Config) mysqlConfig() (*mysql.Config, error) {
+	if cfg.DSN == "" {
+		return nil, errors.New("database dsn is not configured")
+	}
+	mcfg, err := mysql.ParseDSN(cfg.DSN)
+	if err != nil {
+		return nil, err
+	}
+	mcfg.ParseTime = true
+	// Upserts report zero affected rows for unchanged rows only without
+	// CLIENT_FOUND_ROWS, and repositories rely on that to detect new rows.
+	mcfg.ClientFoundRows = false
+	if cfg.User != "" {
+		mcfg.User = cfg.User
+	}
+	if cfg.Password != "" {
+		mcfg.Passwd = cfg.Password
+	}
+	if v := os.Getenv(UserEnv); v != "" {
+		mcfg.User = v
+	}
+	if v := os.Getenv(PasswordEnv); v != "" {
+		mcfg.Passwd = v
+	}
+	if cfg.PasswordFile != "" {
+		b, err := os.ReadFile(cfg.PasswordFile)
+		if err != nil {
+			return nil, fmt.Errorf("read database password file: %w", err)
+		}
+		mcfg.Passwd = strings.TrimSpace(string(b))
+	}
+	if cfg.TLS.Enabled {
+		if mcfg.TLS, err = cfg.TLS.tlsConfig(); err != nil {
+			return nil, err
+		}
+	}
+	return mcfg, nil
+}
+
+func (c TLSConfig) tlsConfig() (*tls.Config, error) {
+	res := &tls.Config{
+		ServerName:         c.ServerName,
+		InsecureSkipVerify: c.InsecureSkipVerify,
+	}
+	if c.CAFile != "" {
+		pem, err := os.ReadFile(c.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("read database CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", c.CAFile)
+		}
+		res.RootCAs = pool
+	}
+	if c.CertFile != "" || c.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("load database client certificate: %w", err)
+		}
+		res.Certificates = []tls.Certificate{cert}
+	}
+	return res, nil
+}
@@ -0,0 +1,110 @@
+package sqldb
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCredentials(t *testing.T) {
+	passwordFile := filepath.Join(t.TempDir(), "password")
+	if err := os.WriteFile(passwordFile, []byte("from-file\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	testCases := []struct {
+		desc     string
+		cfg      Config
+		env      map[string]string
+		wantUser string
+		wantPass string
+	}{
+		{
+			desc:     "config",
+			cfg:      Config{DSN: "tcp(localhost:3306)/movieexample", User: "root", Password: "password"},
+			wantUser: "root",
+			wantPass: "password",
+		},
+		{
+			desc:     "env overrides config",
+			cfg:      Config{DSN: "tcp(localhost:3306)/movieexample", User: "root", Password: "password"},
+			env:      map[string]string{UserEnv: "svc", PasswordEnv: "from-env"},
+			wantUser: "svc",
+			wantPass: "from-env",
+		},
+		{
+			desc:     "found rows are not reported",
+			cfg:      Config{DSN: "tcp(localhost:3306)/movieexample?clientFoundRows=true", User: "root"},
+			wantUser: "root",
+		},
+		{
+			desc:     "file overrides env",
+			cfg:      Config{DSN: "tcp(localhost:3306)/movieexample", PasswordFile: passwordFile},
+			env:      map[string]string{PasswordEnv: "from-env"},
+			wantPass: "from-file",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Setenv(UserEnv, "")
+			t.Setenv(PasswordEnv, "")
+			for k, v := range tc.env {
+				t.Setenv(k, v)
+			}
+			mcfg, err := tc.cfg.mysqlConfig()
+			assert.NoError(t, err)
+			assert.Equal(t, tc.wantUser, mcfg.User)
+			assert.Equal(t, tc.wantPass, mcfg.Passwd)
+			assert.True(t, mcfg.ParseTime)
+			assert.False(t, mcfg.ClientFoundRows)
+		})
+	}
+}
+
+// fakePool records the pool settings applied to it.
+type fakePool struct {
+	set map[string]any
+}
+
+func (p *fakePool) SetMaxOpenConns(n int)              { p.set["maxOpenConns"] = n }
+func (p *fakePool) SetMaxIdleConns(n int)              { p.set["maxIdleConns"] = n }
+func (p *fakePool) SetConnMaxLifetime(d time.Duration) { p.set["connMaxLifetime"] = d }
+func (p *fakePool) SetConnMaxIdleTime(d time.Duration) { p.set["connMaxIdleTime"] = d }
+
+func TestConfigurePool(t *testing.T) {
+	testCases := []struct {
+		desc string
+		cfg  Config
+		want map[string]any
+	}{
+		{
+			desc: "unset settings keep the defaults",
+			cfg:  Config{DSN: "tcp(localhost:3306)/movieexample"},
+			want: map[string]any{},
+		},
+		{
+			desc: "set settings are applied",
+			cfg:  Config{MaxOpenConns: 10, MaxIdleConns: 5, ConnMaxLifetime: time.Hour, ConnMaxIdleTime: time.Minute},
+			want: map[string]any{
+				"maxOpenConns":    10,
+				"maxIdleConns":    5,
+				"connMaxLifetime": time.Hour,
+				"connMaxIdleTime": time.Minute,
+			},
+		},
+		{
+			desc: "partial settings",
+			cfg:  Config{MaxOpenConns: 10},
+			want: map[string]any{"maxOpenConns": 10},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			p := &fakePool{set: map[string]any{}}
+			tc.cfg.configurePool(p)
+			assert.Equal(t, tc.want, p.set)
+		})
+	}
+}
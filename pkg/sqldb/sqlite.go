@@ -0,0 +1,37 @@
+package sqldb
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	_ "modernc.org/sqlite"
+)
+
+// SQLiteConfig defines where a SQLite database is stored.
+type SQLiteConfig struct {
+	// Path is the database file, created if missing. ":memory:" keeps the
+	// database in memory for the lifetime of the process.
+	Path string `yaml:"path"`
+}
+
+// OpenSQLite opens the SQLite database described by cfg. Connections are
+// limited to one so that writers never contend for the database lock and an
+// in-memory database is shared by all callers.
+func OpenSQLite(ctx context.Context, cfg SQLiteConfig) (*sql.DB, error) {
+	if cfg.Path == "" {
+		return nil, errors.New("sqlite path is not configured")
+	}
+	db, err := sql.Open("sqlite", cfg.Path+"?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)")
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxOpenConns(1)
+	db.SetConnMaxLifetime(0)
+	db.SetConnMaxIdleTime(0)
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
@@ -0,0 +1,113 @@
+// Package validate checks struct fields against rules declared in `validate` struct tags.
+//
+// Rules are comma separated. Supported rules are:
+//
+//	required  the field must not be empty
+//	min=N     strings must have at least N characters, numbers must be at least N
+//	max=N     strings must have at most N characters, numbers must be at most N
+//
+// Violations are reported using the field's JSON name when it has one.
+package validate
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Violation describes a single field failing a rule.
+type Violation struct {
+	Field       string `json:"field"`
+	Description string `json:"description"`
+}
+
+// Error is returned when one or more fields fail validation.
+type Error struct {
+	Violations []Violation
+}
+
+func (e *Error) Error() string {
+	parts := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		parts = append(parts, v.Field+": "+v.Description)
+	}
+	return strings.Join(parts, "; ")
+}
+
+// Struct validates the fields of the struct v points to and returns an *Error
+// listing every violation, or nil if all rules hold.
+func Struct(v any) error {
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	if rv.Kind() != reflect.Struct {
+		return fmt.Errorf("validate: expected a struct, got %v", rv.Kind())
+	}
+	var violations []Violation
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		f := rt.Field(i)
+		tag, ok := f.Tag.Lookup("validate")
+		if !ok || !f.IsExported() {
+			continue
+		}
+		for _, rule := range strings.Split(tag, ",") {
+			if desc := check(rv.Field(i), rule); desc != "" {
+				violations = append(violations, Violation{Field: fieldName(f), Description: desc})
+			}
+		}
+	}
+	if len(violations) > 0 {
+		return &Error{Violations: violations}
+	}
+	return nil
+}
+
+func check(v reflect.Value, rule string) string {
+	name, arg, _ := strings.Cut(rule, "=")
+	switch name {
+	case "required":
+		if v.IsZero() || (v.Kind() == reflect.String && strings.TrimSpace(v.String()) == "") {
+			return "must not be empty"
+		}
+	case "min", "max":
+		limit, err := strconv.ParseFloat(arg, 64)
+		if err != nil {
+			panic(fmt.Sprintf("validate: invalid rule %q", rule))
+		}
+		n, unit := size(v)
+		if name == "min" && n < limit {
+			return fmt.Sprintf("must be at least %s%s", arg, unit)
+		}
+		if name == "max" && n > limit {
+			return fmt.Sprintf("must be at most %s%s", arg, unit)
+		}
+	default:
+		panic(fmt.Sprintf("validate: unknown rule %q", rule))
+	}
+	return ""
+}
+
+// size returns the value compared by min and max rules and the unit it is measured in.
+func size(v reflect.Value) (float64, string) {
+	switch v.Kind() {
+	case reflect.String:
+		return float64(utf8.RuneCountInString(v.String())), " characters"
+	case reflect.Slice, reflect.Map:
+		return float64(v.Len()), " items"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), ""
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), ""
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), ""
+	}
+	panic(fmt.Sprintf("validate: min and max are not supported on %v", v.Kind()))
+}
+
+func fieldName(f reflect.StructField) string {
+	if name, _, _ := strings.Cut(f.Tag.Get("json"), ","); name != "" && name != "-" {
+		return name
+	}
+	return f.Name
+}
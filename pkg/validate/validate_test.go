@@ -0,0 +1,54 @@
+package validate
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testStruct struct {
+	ID    string   `json:"id" validate:"required,max=5"`
+	Name  string   `validate:"min=2"`
+	Tags  []string `json:"tags" validate:"max=1"`
+	Count int      `json:"count" validate:"min=1,max=10"`
+}
+
+func TestStruct(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		input   testStruct
+		wantErr []Violation
+	}{
+		{
+			desc:  "valid",
+			input: testStruct{ID: "abc", Name: "ab", Count: 3},
+		},
+		{
+			desc:  "violations",
+			input: testStruct{ID: " ", Name: "é", Tags: []string{"a", "b"}, Count: 11},
+			wantErr: []Violation{
+				{Field: "id", Description: "must not be empty"},
+				{Field: "Name", Description: "must be at least 2 characters"},
+				{Field: "tags", Description: "must be at most 1 items"},
+				{Field: "count", Description: "must be at most 10"},
+			},
+		},
+		{
+			desc:  "multibyte strings count characters",
+			input: testStruct{ID: strings.Repeat("é", 5), Name: "ab", Count: 1},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.desc, func(t *testing.T) {
+			err := Struct(&tt.input)
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+				return
+			}
+			var verr *Error
+			assert.ErrorAs(t, err, &verr)
+			assert.Equal(t, tt.wantErr, verr.Violations)
+		})
+	}
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"fmt"
+	"maps"
 	"net"
 	"net/http"
 	"os"
@@ -13,11 +14,14 @@ import (
 
 	"github.com/Aditya-Chowdhary/micro-movies/gen"
 	"github.com/Aditya-Chowdhary/micro-movies/pkg/discovery"
-	"github.com/Aditya-Chowdhary/micro-movies/pkg/discovery/consul"
+	"github.com/Aditya-Chowdhary/micro-movies/pkg/discovery/setup"
+	"github.com/Aditya-Chowdhary/micro-movies/pkg/health"
 	"github.com/Aditya-Chowdhary/micro-movies/pkg/tracing"
 	"github.com/Aditya-Chowdhary/micro-movies/rating/internal/controller/rating"
 	grpchandler "github.com/Aditya-Chowdhary/micro-movies/rating/internal/handler/grpc"
 	"github.com/Aditya-Chowdhary/micro-movies/rating/internal/repository/memory"
+	"github.com/Aditya-Chowdhary/micro-movies/rating/internal/repository/mysql"
+	"github.com/Aditya-Chowdhary/micro-movies/rating/internal/repository/sqlite"
 
 	"github.com/uber-go/tally"
 	"github.com/uber-go/tally/prometheus"
@@ -26,6 +30,8 @@ import (
 	"go.opentelemetry.io/otel/propagation"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/reflection"
 	"gopkg.in/yaml.v3"
 )
@@ -44,6 +50,14 @@ func main() {
 	if err := yaml.NewDecoder(f).Decode(&cfg); err != nil {
 		logger.Fatal("Failed to parse configuration", zap.Error(err))
 	}
+
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(context.Background(), cfg.Repository, os.Args[2:]); err != nil {
+			logger.Fatal("Failed to migrate the database", zap.Error(err))
+		}
+		return
+	}
+
 	port := cfg.API.Port
 
 	logger.Info("Starting the rating service", zap.Int("port", port))
@@ -85,30 +99,83 @@ func main() {
 	}).Counter("service_started")
 	counter.Inc(1)
 
-	// ! Code for using consul service registry
-
-	registry, err := consul.NewRegistry("localhost:8500")
+	// ! Code for using the configured service registry
+	registry, closeRegistry, err := setup.NewRegistry(cfg.Registry)
 	if err != nil {
-		logger.Fatal("Failed to create consule registry on port 8500: ", zap.Error(err))
+		logger.Fatal("Failed to create service registry", zap.Error(err))
 	}
+	defer closeRegistry()
 
-	instanceID := discovery.GenerateInstanceID(serviceName)
-	if err := registry.Register(ctx, instanceID, serviceName, fmt.Sprintf("localhost:%d", port)); err != nil {
-		logger.Fatal("Failed to generate instanceID for ratings: ", zap.Error(err))
+	hostPort := fmt.Sprintf("localhost:%d", port)
+	instanceID := cfg.Discovery.InstanceID
+	if instanceID == "" {
+		if instanceID, err = discovery.GenerateInstanceID(serviceName, hostPort); err != nil {
+			logger.Fatal("Failed to generate instance ID", zap.Error(err))
+		}
+	}
+	instanceMetadata := map[string]string{discovery.MetadataProtocol: "grpc"}
+	maps.Copy(instanceMetadata, cfg.Discovery.Metadata)
+	if cfg.Discovery.Zone != "" {
+		instanceMetadata[discovery.MetadataZone] = cfg.Discovery.Zone
 	}
 
+	healthServer := grpchealth.NewServer()
+	checker := health.NewChecker(healthServer, health.Options{}, gen.RatingService_ServiceDesc.ServiceName)
+	checker.OnChange = func(err error) {
+		if err != nil {
+			logger.Warn("Service is not ready", zap.Error(err))
+		} else {
+			logger.Info("Service is ready")
+		}
+	}
+	lifecycle := discovery.NewLifecycle(registry, discovery.Registration{
+		InstanceID:  instanceID,
+		ServiceName: serviceName,
+		HostPort:    hostPort,
+		Metadata:    instanceMetadata,
+	})
+	lifecycle.OnError = func(err error) {
+		logger.Warn("Failed to keep the service registered", zap.Error(err))
+	}
+	lifecycle.Ready = checker.Ready
+	deregistered := make(chan struct{})
 	go func() {
-		for {
-			if err := registry.ReportHealthyState(instanceID, serviceName); err != nil {
-				logger.Error("Failed to report healthy state", zap.Error(err))
-			}
-			time.Sleep(1 * time.Second)
+		defer close(deregistered)
+		if err := lifecycle.Run(ctx); err != nil {
+			logger.Error("Failed to deregister the service", zap.Error(err))
 		}
 	}()
-	defer registry.Deregister(ctx, instanceID, serviceName)
+	defer func() {
+		cancel()
+		<-deregistered
+	}()
 
-	repo := memory.New()
-	ctrl := rating.New(repo, nil)
+	var ctrl *rating.Controller
+	switch cfg.Repository.Driver {
+	case "", "memory":
+		ctrl = rating.New(memory.New(), nil)
+	case "mysql", "sqlite":
+		db, migrator, err := openDatabase(ctx, cfg.Repository)
+		if err != nil {
+			logger.Fatal("Failed to connect to the database", zap.Error(err))
+		}
+		defer db.Close()
+		checker.Add("database", health.Ping(db))
+		if cfg.Repository.Migrate {
+			n, err := migrator.Up(ctx)
+			if err != nil {
+				logger.Fatal("Failed to migrate the database", zap.Error(err))
+			}
+			logger.Info("Applied database migrations", zap.Int("count", n))
+		}
+		if cfg.Repository.Driver == "sqlite" {
+			ctrl = rating.New(sqlite.New(db), nil)
+		} else {
+			ctrl = rating.New(mysql.New(db), nil)
+		}
+	default:
+		logger.Fatal("Unknown repository driver", zap.String("driver", cfg.Repository.Driver))
+	}
 	h := grpchandler.New(ctrl)
 	lis, err := net.Listen("tcp", fmt.Sprintf("localhost:%v", port))
 	if err != nil {
@@ -118,6 +185,8 @@ func main() {
 	srv := grpc.NewServer(grpc.StatsHandler(otelgrpc.NewServerHandler()))
 	reflection.Register(srv)
 	gen.RegisterRatingServiceServer(srv, h)
+	healthpb.RegisterHealthServer(srv, healthServer)
+	go checker.Run(ctx)
 
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/Aditya-Chowdhary/micro-movies/pkg/migrate"
+	"github.com/Aditya-Chowdhary/micro-movies/pkg/sqldb"
+	"github.com/Aditya-Chowdhary/micro-movies/rating/internal/repository/mysql"
+	"github.com/Aditya-Chowdhary/micro-movies/rating/internal/repository/sqlite"
+)
+
+// openDatabase connects to the database of the configured repository driver
+// and creates a migrator for its schema.
+func openDatabase(ctx context.Context, cfg repositoryConfig) (*sql.DB, *migrate.Migrator, error) {
+	var db *sql.DB
+	var newMigrator func(*sql.DB) (*migrate.Migrator, error)
+	var err error
+	switch cfg.Driver {
+	case "mysql":
+		db, err = sqldb.Open(ctx, cfg.MySQL)
+		newMigrator = mysql.NewMigrator
+	case "sqlite":
+		db, err = sqldb.OpenSQLite(ctx, cfg.SQLite)
+		newMigrator = sqlite.NewMigrator
+	default:
+		return nil, nil, fmt.Errorf("repository driver %q has no database", cfg.Driver)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	migrator, err := newMigrator(db)
+	if err != nil {
+		db.Close()
+		return nil, nil, err
+	}
+	return db, migrator, nil
+}
+
+// runMigrate runs the migrate subcommand against the configured database. The
+// memory repository has no schema, so with the memory driver, the default, it
+// migrates the MySQL database.
+func runMigrate(ctx context.Context, cfg repositoryConfig, args []string) error {
+	if len(args) == 0 {
+		return errors.New(migrate.Usage)
+	}
+	if cfg.Driver == "" || cfg.Driver == "memory" {
+		cfg.Driver = "mysql"
+	}
+	db, migrator, err := openDatabase(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	return migrate.RunCommand(ctx, migrator, args, os.Stdout)
+}
@@ -2,30 +2,37 @@ package memory
 
 import (
 	"context"
+	"sync"
 
 	"github.com/Aditya-Chowdhary/micro-movies/rating/internal/repository"
 	"github.com/Aditya-Chowdhary/micro-movies/rating/pkg/model"
 )
 
 type Repository struct {
+	sync.RWMutex
 	data map[model.RecordType]map[model.RecordID][]model.Rating
 }
 
 func New() *Repository {
-	return &Repository{map[model.RecordType]map[model.RecordID][]model.Rating{}}
+	return &Repository{data: map[model.RecordType]map[model.RecordID][]model.Rating{}}
 }
 
 func (r *Repository) Get(ctx context.Context, recordID model.RecordID, recordType model.RecordType) ([]model.Rating, error) {
-	if _, ok := r.data[recordType]; !ok {
-		return nil, repository.ErrNotFound
-	}
-	if ratings, ok := r.data[recordType][recordID]; !ok || len(ratings) == 0 {
+	r.RLock()
+	defer r.RUnlock()
+
+	ratings := r.data[recordType][recordID]
+	if len(ratings) == 0 {
 		return nil, repository.ErrNotFound
 	}
-	return r.data[recordType][recordID], nil
+	return append([]model.Rating(nil), ratings...), nil
 }
 
+// Put adds rating for a given record.
 func (r *Repository) Put(ctx context.Context, recordID model.RecordID, recordType model.RecordType, rating *model.Rating) error {
+	r.Lock()
+	defer r.Unlock()
+
 	if _, ok := r.data[recordType]; !ok {
 		r.data[recordType] = map[model.RecordID][]model.Rating{}
 	}
@@ -0,0 +1,13 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/Aditya-Chowdhary/micro-movies/rating/internal/repository/repositorytest"
+)
+
+func TestRepository(t *testing.T) {
+	repositorytest.Run(t, func(t *testing.T) repositorytest.Repository {
+		return New()
+	})
+}
@@ -1,13 +1,7 @@
-CREATE TABLE IF NOT EXISTS movies(
-    id VARCHAR(255),
-    title VARCHAR(255),
-    description TEXT,
-    director VARCHAR(255)
-);
-
+-- Uses IF NOT EXISTS so databases created before migrations existed can adopt them.
 CREATE TABLE IF NOT EXISTS ratings (
     record_id VARCHAR(255),
     record_type VARCHAR(255),
     user_id VARCHAR(255),
     value INT
-);
\ No newline at end of file
+);
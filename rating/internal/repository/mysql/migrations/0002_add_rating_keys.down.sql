@@ -0,0 +1,6 @@
+ALTER TABLE ratings
+    DROP INDEX idx_ratings_record,
+    MODIFY record_id VARCHAR(255),
+    MODIFY record_type VARCHAR(255),
+    MODIFY user_id VARCHAR(255),
+    MODIFY value INT;
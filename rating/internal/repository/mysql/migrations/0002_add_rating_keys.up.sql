@@ -0,0 +1,9 @@
+-- Rows missing a column were never readable through the repository.
+DELETE FROM ratings WHERE record_id IS NULL OR record_type IS NULL OR user_id IS NULL OR value IS NULL;
+-- A user may rate a record more than once, so ratings are indexed by record without a unique key.
+ALTER TABLE ratings
+    MODIFY record_id VARCHAR(255) NOT NULL,
+    MODIFY record_type VARCHAR(255) NOT NULL,
+    MODIFY user_id VARCHAR(255) NOT NULL,
+    MODIFY value INT NOT NULL,
+    ADD INDEX idx_ratings_record (record_id, record_type);
@@ -6,8 +6,6 @@ import (
 
 	"github.com/Aditya-Chowdhary/micro-movies/rating/internal/repository"
 	"github.com/Aditya-Chowdhary/micro-movies/rating/pkg/model"
-
-	_ "github.com/go-sql-driver/mysql"
 )
 
 // Repository defines a MYSQL-based rating repository
@@ -15,13 +13,10 @@ type Repository struct {
 	db *sql.DB
 }
 
-// New creates a new MYSQL-based rating repository
-func New() (*Repository, error) {
-	db, err := sql.Open("mysql", "root:password@/movieexample")
-	if err != nil {
-		return nil, err
-	}
-	return &Repository{db}, nil
+// New creates a new MYSQL-based rating repository backed by db.
+// Use sqldb.Open to obtain a configured connection pool.
+func New(db *sql.DB) *Repository {
+	return &Repository{db}
 }
 
 // Get retrieves all ratings for a given record
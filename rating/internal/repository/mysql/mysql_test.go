@@ -0,0 +1,38 @@
+package mysql
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/Aditya-Chowdhary/micro-movies/pkg/sqldb"
+	"github.com/Aditya-Chowdhary/micro-movies/rating/internal/repository/repositorytest"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestRepository runs against the database named by MYSQL_TEST_DSN, e.g. the
+// container started by `make db/create`, and is skipped when it is unset.
+// The suite empties the ratings table.
+func TestRepository(t *testing.T) {
+	dsn := os.Getenv("MYSQL_TEST_DSN")
+	if dsn == "" {
+		t.Skip("MYSQL_TEST_DSN is not set")
+	}
+	ctx := context.Background()
+	db, err := sqldb.Open(ctx, sqldb.Config{DSN: dsn})
+	require.NoError(t, err)
+	defer db.Close()
+	migrator, err := NewMigrator(db)
+	require.NoError(t, err)
+	_, err = migrator.Up(ctx)
+	require.NoError(t, err)
+
+	repositorytest.Run(t, func(t *testing.T) repositorytest.Repository {
+		for _, table := range []string{"ratings"} {
+			_, err := db.ExecContext(ctx, "DELETE FROM "+table)
+			require.NoError(t, err)
+		}
+		return New(db)
+	})
+}
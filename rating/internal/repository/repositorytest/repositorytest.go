@@ -0,0 +1,101 @@
+// Package repositorytest provides a conformance suite that every rating
+// repository implementation must pass.
+package repositorytest
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/Aditya-Chowdhary/micro-movies/rating/internal/repository"
+	"github.com/Aditya-Chowdhary/micro-movies/rating/pkg/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Repository is the rating repository behaviour under test.
+type Repository interface {
+	Get(ctx context.Context, recordID model.RecordID, recordType model.RecordType) ([]model.Rating, error)
+	Put(ctx context.Context, recordID model.RecordID, recordType model.RecordType, rating *model.Rating) error
+}
+
+// Run runs the conformance suite. newRepo must return an empty repository
+// for every call.
+func Run(t *testing.T, newRepo func(t *testing.T) Repository) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, r Repository)
+	}{
+		{"NotFound", testNotFound},
+		{"PutGet", testPutGet},
+		{"RepeatedRatings", testRepeatedRatings},
+		{"Concurrency", testConcurrency},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.fn(t, newRepo(t))
+		})
+	}
+}
+
+const movie = model.RecordType("movie")
+
+func testNotFound(t *testing.T, r Repository) {
+	ctx := context.Background()
+	_, err := r.Get(ctx, "1", movie)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+
+	require.NoError(t, r.Put(ctx, "1", movie, &model.Rating{UserID: "u1", Value: 5}))
+	_, err = r.Get(ctx, "1", "other")
+	assert.ErrorIs(t, err, repository.ErrNotFound, "ratings are scoped to the record type")
+	_, err = r.Get(ctx, "2", movie)
+	assert.ErrorIs(t, err, repository.ErrNotFound, "ratings are scoped to the record id")
+}
+
+func testPutGet(t *testing.T, r Repository) {
+	ctx := context.Background()
+	require.NoError(t, r.Put(ctx, "1", movie, &model.Rating{UserID: "u1", Value: 3}))
+	require.NoError(t, r.Put(ctx, "1", movie, &model.Rating{UserID: "u2", Value: 5}))
+
+	got, err := r.Get(ctx, "1", movie)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []model.Rating{{UserID: "u1", Value: 3}, {UserID: "u2", Value: 5}}, got)
+}
+
+func testRepeatedRatings(t *testing.T, r Repository) {
+	ctx := context.Background()
+	require.NoError(t, r.Put(ctx, "1", movie, &model.Rating{UserID: "u1", Value: 3}))
+	require.NoError(t, r.Put(ctx, "1", movie, &model.Rating{UserID: "u1", Value: 4}))
+
+	got, err := r.Get(ctx, "1", movie)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []model.Rating{{UserID: "u1", Value: 3}, {UserID: "u1", Value: 4}}, got,
+		"every rating by a user is kept")
+}
+
+func testConcurrency(t *testing.T, r Repository) {
+	ctx := context.Background()
+	const users = 20
+
+	var wg sync.WaitGroup
+	errs := make(chan error, users)
+	for i := 0; i < users; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			userID := model.UserID(fmt.Sprintf("u%d", i))
+			errs <- r.Put(ctx, "1", movie, &model.Rating{UserID: userID, Value: model.RatingValue(i%5 + 1)})
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		require.NoError(t, err)
+	}
+
+	got, err := r.Get(ctx, "1", movie)
+	require.NoError(t, err)
+	assert.Len(t, got, users)
+}
@@ -0,0 +1,8 @@
+-- A user may rate a record more than once, so ratings are indexed by record without a unique key.
+CREATE TABLE ratings (
+    record_id TEXT NOT NULL,
+    record_type TEXT NOT NULL,
+    user_id TEXT NOT NULL,
+    value INTEGER NOT NULL
+);
+CREATE INDEX idx_ratings_record ON ratings (record_id, record_type);
@@ -0,0 +1,62 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/Aditya-Chowdhary/micro-movies/rating/internal/repository"
+	"github.com/Aditya-Chowdhary/micro-movies/rating/pkg/model"
+)
+
+// Repository defines a SQLite-based rating repository
+type Repository struct {
+	db *sql.DB
+}
+
+// New creates a new SQLite-based rating repository backed by db.
+// Use sqldb.OpenSQLite to open the database and NewMigrator to create its schema.
+func New(db *sql.DB) *Repository {
+	return &Repository{db}
+}
+
+// Get retrieves all ratings for a given record
+func (r *Repository) Get(ctx context.Context, recordID model.RecordID, recordType model.RecordType) ([]model.Rating, error) {
+	query := "SELECT user_id, value FROM ratings WHERE record_id = ? AND record_type = ? ORDER BY user_id"
+
+	rows, err := r.db.QueryContext(ctx, query, recordID, recordType)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []model.Rating
+	for rows.Next() {
+		var (
+			userID string
+			value  int32
+		)
+		if err := rows.Scan(&userID, &value); err != nil {
+			return nil, err
+		}
+		res = append(res, model.Rating{
+			UserID: model.UserID(userID),
+			Value:  model.RatingValue(value),
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(res) == 0 {
+		return nil, repository.ErrNotFound
+	}
+	return res, nil
+}
+
+// Put adds rating for a given record
+func (r *Repository) Put(ctx context.Context, recordID model.RecordID, recordType model.RecordType, rating *model.Rating) error {
+	query := `INSERT INTO ratings (record_id, record_type, user_id, value)
+	VALUES (?, ?, ?, ?)`
+
+	_, err := r.db.ExecContext(ctx, query, recordID, recordType, rating.UserID, rating.Value)
+	return err
+}
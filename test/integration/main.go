@@ -33,6 +33,7 @@ func main() {
 
 	ctx := context.Background()
 	registry := memory.NewRegistry()
+	defer registry.Close()
 
 	log.Println("Setting up service handlers and clients")
 
@@ -92,7 +93,9 @@ func main() {
 	log.Println("Getting movie details via movie service")
 
 	wantMovieDetails := &gen.MovieDetails{
-		Metadata: m,
+		Metadata:       m,
+		MetadataStatus: gen.ComponentStatus_COMPONENT_STATUS_OK,
+		RatingStatus:   gen.ComponentStatus_COMPONENT_STATUS_NOT_FOUND,
 	}
 
 	getMovieDetailsResp, err := movieClient.GetMovieDetails(ctx, &gen.GetMovieDetailsRequest{MovieId: m.Id})
@@ -100,7 +103,7 @@ func main() {
 		log.Fatalf("get movie details: %v", err)
 	}
 	if diff := cmp.Diff(getMovieDetailsResp.MovieDetails, wantMovieDetails, cmpopts.IgnoreUnexported(gen.MovieDetails{}, gen.Metadata{})); diff != "" {
-		log.Fatalf("get movie details after put mismatch: %v", err)
+		log.Fatalf("get movie details after put mismatch: %v", diff)
 	}
 
 	log.Println("Saving first rating via rating service")
@@ -160,8 +163,9 @@ func main() {
 		log.Fatalf("get movie details: %v", err)
 	}
 	wantMovieDetails.Rating = wantRating
+	wantMovieDetails.RatingStatus = gen.ComponentStatus_COMPONENT_STATUS_OK
 	if diff := cmp.Diff(getMovieDetailsResp.MovieDetails, wantMovieDetails, cmpopts.IgnoreUnexported(gen.MovieDetails{}, gen.Metadata{})); diff != "" {
-		log.Fatalf("get movie details after put mismatch: %v", err)
+		log.Fatalf("get movie details after put mismatch: %v", diff)
 	}
 
 	log.Println("Integration test execution successful")
@@ -182,8 +186,11 @@ func startMetadataService(ctx context.Context, registry discovery.Registry) *grp
 			panic(err)
 		}
 	}()
-	id := discovery.GenerateInstanceID(metadataServiceName)
-	if err := registry.Register(ctx, id, metadataServiceName, metadataServiceAddr); err != nil {
+	id, err := discovery.GenerateInstanceID(metadataServiceName, metadataServiceAddr)
+	if err != nil {
+		panic(err)
+	}
+	if err := registry.Register(ctx, id, metadataServiceName, metadataServiceAddr, nil); err != nil {
 		panic(err)
 	}
 	return srv
@@ -203,8 +210,11 @@ func startRatingService(ctx context.Context, registry discovery.Registry) *grpc.
 			panic(err)
 		}
 	}()
-	id := discovery.GenerateInstanceID(ratingServiceName)
-	if err := registry.Register(ctx, id, ratingServiceName, ratingServiceAddr); err != nil {
+	id, err := discovery.GenerateInstanceID(ratingServiceName, ratingServiceAddr)
+	if err != nil {
+		panic(err)
+	}
+	if err := registry.Register(ctx, id, ratingServiceName, ratingServiceAddr, nil); err != nil {
 		panic(err)
 	}
 	return srv
@@ -224,8 +234,11 @@ func startMovieService(ctx context.Context, registry discovery.Registry) *grpc.S
 			panic(err)
 		}
 	}()
-	id := discovery.GenerateInstanceID(movieServiceName)
-	if err := registry.Register(ctx, id, movieServiceName, movieServiceAddr); err != nil {
+	id, err := discovery.GenerateInstanceID(movieServiceName, movieServiceAddr)
+	if err != nil {
+		panic(err)
+	}
+	if err := registry.Register(ctx, id, movieServiceName, movieServiceAddr, nil); err != nil {
 		panic(err)
 	}
 	return srv